@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/shunito/pollenow/internal/apikey"
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/httpx"
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/usage"
+)
+
+// transports is shared by every API client in the process, so clients
+// with the same proxy and CA settings reuse connections.
+var transports httpx.Transports
+
+// usageLedger is shared by every API client in the process, so the daily
+// count it caches includes requests to both APIs.
+var usageLedger = sync.OnceValue(func() *usage.Ledger { return usage.New("") })
+
+// softCapWarning prints the soft cap warning once per process, though
+// each API's client has its own Limiter.
+var softCapWarning sync.Once
+
+// clientMode selects how much of the HTTP stack wraps the API clients.
+type clientMode int
+
+const (
+	// modeDirect sends each request once and records it in the usage
+	// ledger. Used by diagnostics, which should see failures as they are.
+	modeDirect clientMode = iota
+	// modeManaged adds daily caps and retries.
+	modeManaged
+	// modeProbe sends each request once and leaves it out of the usage
+	// ledger, for requests that aren't API calls, such as doctor's clock
+	// check.
+	modeProbe
+)
+
+// newAPIClients builds the Geocoding and Pollen clients from the endpoint
+// settings in cfg.
+func newAPIClients(cfg *config.Config, key apikey.Source, mode clientMode) (*geocoding.GoogleGeocoder, *pollen.GooglePollenClient, error) {
+	geoHTTP, err := newHTTPClient(cfg, "geocoding", mode)
+	if err != nil {
+		return nil, nil, err
+	}
+	pollenHTTP, err := newHTTPClient(cfg, "pollen", mode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lang := language(cfg)
+	g := geocoding.NewGoogleGeocoderWithSource(key, geoHTTP).
+		WithBaseURL(cfg.Endpoint("geocoding").BaseURL).
+		WithLanguage(lang).
+		WithLogger(logger)
+	p := pollen.NewGooglePollenClientWithSource(key, pollenHTTP).
+		WithBaseURL(cfg.Endpoint("pollen").BaseURL).
+		WithLanguage(lang).
+		WithLogger(logger)
+	return g, p, nil
+}
+
+// language returns the language to request from the APIs: --lang, then
+// the language setting. Empty means the API default, English.
+func language(cfg *config.Config) string {
+	if flagLang != "" {
+		return flagLang
+	}
+	return cfg.Language
+}
+
+// Environment variables that record API exchanges to, or replay them from,
+// a directory.
+const (
+	envRecord = "POLLENOW_RECORD"
+	envReplay = "POLLENOW_REPLAY"
+)
+
+// offline reports whether API responses come from the demo mock or a
+// replayed recording rather than Google. Offline runs need no key, are not
+// counted or capped, and leave the cache and history untouched.
+func offline() bool {
+	return flagDemo != "" || os.Getenv(envReplay) != ""
+}
+
+// newHTTPClient builds the HTTP stack for one API. From the outside in:
+// redaction, so no error carries a credential; daily caps, so a refusal is
+// never retried; retries; the usage ledger, so every attempt that reaches
+// the network is counted; a tracing span per attempt; the User-Agent; the
+// optional recording of exact upstream exchanges; and finally whatever
+// answers the request (see baseClient).
+func newHTTPClient(cfg *config.Config, api string, mode clientMode) (httpx.Client, error) {
+	ep := cfg.Endpoint(api)
+	base, err := baseClient(ep)
+	if err != nil {
+		return nil, fmt.Errorf("%s API: %w", api, err)
+	}
+	if dir := os.Getenv(envRecord); dir != "" {
+		base = httpx.NewRecording(base, dir)
+	}
+
+	agent := ep.UserAgent
+	if agent == "" {
+		agent = "pollenow/" + Version
+	}
+
+	ledger := usageLedger()
+	var c httpx.Client = httpx.NewUserAgent(base, agent)
+	c = httpx.NewTracing(c)
+	if !offline() && mode != modeProbe {
+		c = usage.NewRecorder(c, ledger)
+	}
+	if mode == modeManaged {
+		// Retries stay on offline so recorded and mocked 429s play out as
+		// they would for real.
+		c = httpx.NewRetrying(c, httpx.DefaultRetryPolicy).WithLogger(logger)
+		if !offline() {
+			c = usage.NewLimiter(c, ledger, usageCaps(cfg), func(msg string) {
+				softCapWarning.Do(func() {
+					fmt.Fprintf(os.Stderr, "  pollenow: %s\n", msg)
+				})
+			})
+		}
+	}
+	return httpx.NewRedacting(c), nil
+}
+
+// baseClient answers requests from a replayed recording, the --demo mock,
+// or Google over the shared transport for ep's proxy and CA settings.
+func baseClient(ep config.ResolvedEndpoint) (httpx.Client, error) {
+	if dir := os.Getenv(envReplay); dir != "" {
+		return httpx.NewReplay(dir)
+	}
+	if flagDemo != "" {
+		tr, err := demoTransport()
+		if err != nil {
+			return nil, err
+		}
+		return &http.Client{Transport: tr, Timeout: ep.Timeout}, nil
+	}
+	tr, err := transports.Get(httpx.TransportOptions{Proxy: ep.Proxy, CABundle: ep.CABundle})
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: tr, Timeout: ep.Timeout}, nil
+}
+
+// clockURL is the root of the Pollen API host, asked for the time by doctor.
+func clockURL(cfg *config.Config) string {
+	base := cfg.Endpoint("pollen").BaseURL
+	if base == "" {
+		base = pollen.DefaultBaseURL
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return pollen.DefaultBaseURL
+	}
+	return u.Scheme + "://" + u.Host + "/"
+}
@@ -1,16 +1,30 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"github.com/shunito/pollenow/internal/config"
 	"github.com/shunito/pollenow/internal/ui"
 )
 
+var (
+	flagConfigOrigin bool
+	flagConfigReveal bool
+
+	flagInitAPIKey         string
+	flagInitZIP            string
+	flagInitNonInteractive bool
+	flagInitSkipCheck      bool
+)
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage configuration",
@@ -18,72 +32,167 @@ var configCmd = &cobra.Command{
 	RunE:  runConfigShow,
 }
 
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective config",
+	Long: `Show the effective configuration after merging every layer:
+/etc/pollenow/config.yaml, the user config file, POLLENOW_* environment
+variables, and the --config file. Use --origin to see where each value came from.`,
+	RunE: runConfigShow,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all config keys",
+	Long:  "List every config key with its type, current value, and description.",
+	RunE:  runConfigList,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a config value",
+	Long:  "Print the effective value of a config key. Secrets are redacted unless --reveal is given.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
 var configSetCmd = &cobra.Command{
 	Use:   "set <key> <value>",
 	Short: "Set a config value",
-	Long:  "Set a configuration value. Keys: api_key, default_zip, days",
-	Args:  cobra.ExactArgs(2),
-	RunE:  runConfigSet,
+	Long: `Set a configuration value in the user config file (or the --config file).
+With --profile, the value is set inside that profile. Values are validated
+before they are saved. Run "pollenow config list" to see every key.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a config value",
+	Long:  "Remove a configuration value from the user config file (or from a profile with --profile).",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUnset,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the config file in $EDITOR",
+	Long:  "Open the config file in $VISUAL or $EDITOR. The file is only replaced if the edited version is valid.",
+	RunE:  runConfigEdit,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check config files for errors",
+	Long:  "Parse every config layer and validate each value, reporting all problems found.",
+	RunE:  runConfigValidate,
 }
 
 var configInitCmd = &cobra.Command{
 	Use:   "init",
-	Short: "Interactive setup",
-	Long:  "Run the interactive first-time setup wizard.",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		_, err := runFirstTimeSetup()
-		return err
-	},
+	Short: "First-time setup",
+	Long: `Save your API key and default ZIP code, checking the key against the
+Geocoding and Pollen APIs first. Prompts for anything not given as a flag,
+unless --non-interactive is set or stdin is not a terminal.`,
+	Example: `  pollenow config init
+  pollenow config init --api-key "$KEY" --zip 94025 --non-interactive`,
+	RunE: runConfigInit,
 }
 
 var configPathCmd = &cobra.Command{
 	Use:   "path",
 	Short: "Print config file path",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println(config.Path())
+		p, err := config.WritePath()
+		if err != nil {
+			ui.RenderError(err)
+			return err
+		}
+		fmt.Println(p)
 		return nil
 	},
 }
 
 func init() {
+	for _, c := range []*cobra.Command{configCmd, configShowCmd} {
+		c.Flags().BoolVar(&flagConfigOrigin, "origin", false, "Show where each value came from")
+	}
+	configGetCmd.Flags().BoolVar(&flagConfigReveal, "reveal", false, "Print secret values in full")
+	configInitCmd.Flags().StringVar(&flagInitAPIKey, "api-key", "", "Google API key (default $POLLENOW_API_KEY)")
+	configInitCmd.Flags().StringVar(&flagInitZIP, "zip", "", "Default ZIP code")
+	configInitCmd.Flags().BoolVar(&flagInitNonInteractive, "non-interactive", false, "Never prompt; fail if a required value is missing")
+	configInitCmd.Flags().BoolVar(&flagInitSkipCheck, "skip-check", false, "Save without checking the key against Google")
+
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configValidateCmd)
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configPathCmd)
 }
 
 func runConfigShow(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
+	cfg, origins, err := config.LoadWithOrigins()
 	if err != nil {
 		ui.RenderError(err)
 		return err
 	}
 
-	apiKeyDisplay := "(not set)"
-	if cfg.APIKey != "" {
-		// Redact API key, show first 4 and last 3 chars
-		if len(cfg.APIKey) > 10 {
-			apiKeyDisplay = cfg.APIKey[:4] + "..." + cfg.APIKey[len(cfg.APIKey)-3:]
-		} else {
-			apiKeyDisplay = "***"
+	for _, f := range config.Schema {
+		line := fmt.Sprintf("  %-17s %s", f.Key+":", oneLine(f.Display(cfg)))
+		if flagConfigOrigin {
+			line = fmt.Sprintf("%-48s  [%s]", line, originOf(f, origins))
 		}
+		fmt.Println(line)
+	}
+	if src, err := cfg.KeySource(); err == nil {
+		fmt.Printf("  %-17s %s\n", "api key source:", src.Describe())
+	}
+	if names := cfg.ProfileNames(); len(names) > 0 {
+		fmt.Printf("  %-17s %s\n", "profiles:", strings.Join(names, ", "))
+	}
+	if p, err := config.WritePath(); err == nil {
+		fmt.Printf("  %-17s %s\n", "config file:", p)
 	}
 
-	zipDisplay := "(not set)"
-	if cfg.DefaultZIP != "" {
-		zipDisplay = cfg.DefaultZIP
+	return nil
+}
+
+func runConfigInit(cmd *cobra.Command, args []string) error {
+	_, err := runSetup(setupOptions{
+		APIKey:      flagInitAPIKey,
+		ZIP:         flagInitZIP,
+		Interactive: !flagInitNonInteractive && stdinIsTerminal(),
+		SkipCheck:   flagInitSkipCheck,
+	})
+	if err != nil {
+		ui.RenderError(err)
 	}
+	return err
+}
 
-	fmt.Printf("  api_key:     %s\n", apiKeyDisplay)
-	fmt.Printf("  default_zip: %s\n", zipDisplay)
-	fmt.Printf("  days:        %d\n", cfg.Days)
-	fmt.Printf("  config file: %s\n", config.Path())
+func runConfigList(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
 
+	for _, f := range config.Schema {
+		fmt.Printf("  %-17s %-7s %-20s %s\n", f.Key, f.Kind, oneLine(f.Display(cfg)), f.Description)
+	}
 	return nil
 }
 
-func runConfigSet(cmd *cobra.Command, args []string) error {
-	key := strings.ToLower(args[0])
-	value := args[1]
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	f, err := config.Lookup(args[0])
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
 
 	cfg, err := config.Load()
 	if err != nil {
@@ -91,28 +200,268 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	switch key {
-	case "api_key":
-		cfg.APIKey = value
-	case "default_zip":
-		cfg.DefaultZIP = value
-	case "days":
-		d, err := strconv.Atoi(value)
-		if err != nil || d < 1 || d > 5 {
-			ui.RenderError(fmt.Errorf("days must be a number between 1 and 5"))
-			return fmt.Errorf("invalid days value")
+	v, ok := f.Get(cfg)
+	if !ok {
+		err := fmt.Errorf("%s is not set", f.Key)
+		ui.RenderError(err)
+		return err
+	}
+	if f.Secret && !flagConfigReveal {
+		v = config.Redact(v)
+	}
+	fmt.Println(v)
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	f, err := config.Lookup(args[0])
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	err = editConfigFile(func(cfg *config.Config) error {
+		if config.ProfileOverride != "" {
+			return cfg.SetProfileKey(config.ProfileOverride, f, args[1])
 		}
-		cfg.Days = d
-	default:
-		ui.RenderError(fmt.Errorf("unknown config key %q — valid keys: api_key, default_zip, days", key))
-		return fmt.Errorf("unknown key: %s", key)
+		return f.Set(cfg, args[1])
+	})
+	if err != nil {
+		ui.RenderError(err)
+		return err
 	}
 
-	if err := config.Save(cfg); err != nil {
+	shown := args[1]
+	if f.Secret {
+		shown = config.Redact(shown)
+	}
+	fmt.Printf("  ✓ %s set to %s%s\n", f.Key, oneLine(shown), profileSuffix())
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	f, err := config.Lookup(args[0])
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	err = editConfigFile(func(cfg *config.Config) error {
+		if config.ProfileOverride != "" {
+			return cfg.UnsetProfileKey(config.ProfileOverride, f)
+		}
+		f.Unset(cfg)
+		return nil
+	})
+	if err != nil {
 		ui.RenderError(err)
 		return err
 	}
 
-	fmt.Printf("  ✓ %s set to %s\n", key, value)
+	fmt.Printf("  ✓ %s unset%s\n", f.Key, profileSuffix())
 	return nil
 }
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	p, err := config.WritePath()
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	original, err := os.ReadFile(p)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		ui.RenderError(fmt.Errorf("reading config: %w", err))
+		return err
+	}
+
+	// Edit a copy so an invalid edit never replaces a working config.
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		ui.RenderError(fmt.Errorf("creating config dir: %w", err))
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".config-*.yaml")
+	if err != nil {
+		ui.RenderError(fmt.Errorf("creating temp file: %w", err))
+		return err
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(original)
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmpPath)
+		ui.RenderError(fmt.Errorf("writing temp file: %w", err))
+		return err
+	}
+
+	if err := runEditor(tmpPath); err != nil {
+		os.Remove(tmpPath)
+		ui.RenderError(err)
+		return err
+	}
+
+	edited, err := os.ReadFile(tmpPath)
+	if err != nil {
+		ui.RenderError(fmt.Errorf("reading edited config: %w", err))
+		return err
+	}
+
+	var cfg config.Config
+	var problems []error
+	if err := yaml.Unmarshal(edited, &cfg); err != nil {
+		problems = append(problems, fmt.Errorf("parsing config: %w", err))
+	} else {
+		problems = cfg.Check()
+	}
+	if len(problems) > 0 {
+		for _, e := range problems {
+			ui.RenderError(e)
+		}
+		err := fmt.Errorf("config not saved — your edits are in %s", tmpPath)
+		ui.RenderError(err)
+		return err
+	}
+
+	if err := os.Chmod(tmpPath, 0o600); err != nil {
+		os.Remove(tmpPath)
+		ui.RenderError(err)
+		return err
+	}
+	if err := os.Rename(tmpPath, p); err != nil {
+		ui.RenderError(fmt.Errorf("saving config: %w", err))
+		return err
+	}
+
+	fmt.Printf("  ✓ Config saved to %s\n", p)
+	return nil
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	userPath, err := config.Path()
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	failed := false
+	layers := []struct{ label, path string }{
+		{"system file", config.SystemPath()},
+		{"user file", userPath},
+	}
+	if config.OverridePath != "" {
+		layers = append(layers, struct{ label, path string }{"--config file", config.OverridePath})
+	}
+
+	for _, l := range layers {
+		if _, err := os.Stat(l.path); errors.Is(err, os.ErrNotExist) && l.label != "--config file" {
+			fmt.Printf("  - %s %s (not present)\n", l.label, l.path)
+			continue
+		}
+		cfg, err := config.LoadFile(l.path)
+		if err != nil {
+			failed = true
+			fmt.Printf("  ✗ %s: %v\n", l.label, err)
+			continue
+		}
+		problems := cfg.Check()
+		if len(problems) == 0 {
+			fmt.Printf("  ✓ %s %s\n", l.label, l.path)
+			continue
+		}
+		failed = true
+		fmt.Printf("  ✗ %s %s\n", l.label, l.path)
+		for _, p := range problems {
+			fmt.Printf("      %v\n", p)
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		failed = true
+		fmt.Printf("  ✗ effective config: %v\n", err)
+	} else {
+		problems := cfg.Check()
+		if err := cfg.Validate(); errors.Is(err, config.ErrNoAPIKey) {
+			problems = append(problems, err)
+		}
+		if len(problems) == 0 {
+			fmt.Println("  ✓ effective config")
+		} else {
+			failed = true
+			fmt.Println("  ✗ effective config")
+			for _, p := range problems {
+				fmt.Printf("      %v\n", p)
+			}
+		}
+	}
+
+	if failed {
+		return errors.New("config is invalid")
+	}
+	return nil
+}
+
+// editConfigFile loads only the writable config file, applies edit, and
+// saves it. Values from the system file or environment are never copied in.
+func editConfigFile(edit func(*config.Config) error) error {
+	p, err := config.WritePath()
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadFile(p)
+	if err != nil {
+		return err
+	}
+	if err := edit(cfg); err != nil {
+		return err
+	}
+	return config.Save(cfg)
+}
+
+// runEditor opens path in $VISUAL, $EDITOR, or vi.
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor variable may include arguments, e.g. "code --wait".
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("running editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+// originOf reports where a schema field's value came from. Nested keys
+// such as thresholds.high share the origin of their top-level key.
+func originOf(f config.Field, origins config.Origins) string {
+	if o := origins[f.Key]; o != "" {
+		return o
+	}
+	top, _, _ := strings.Cut(f.Key, ".")
+	if o := origins[top]; o != "" {
+		return o
+	}
+	return "unset"
+}
+
+func profileSuffix() string {
+	if config.ProfileOverride == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in profile %q", config.ProfileOverride)
+}
+
+// oneLine collapses multi-line YAML values for tabular output.
+func oneLine(s string) string {
+	if !strings.Contains(s, "\n") {
+		return s
+	}
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, "\n", " ")), " ")
+}
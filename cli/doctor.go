@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shunito/pollenow/internal/apikey"
+	"github.com/shunito/pollenow/internal/cache"
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/doctor"
+	"github.com/shunito/pollenow/internal/ui"
+)
+
+var (
+	flagDoctorJSON bool
+	flagDoctorFix  bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose setup problems",
+	Long: `Check the config file and its permissions, the API key source, access to
+the Geocoding and Pollen APIs, the cache, and the system clock.
+
+Exits non-zero if any check fails. Secrets are never printed, so the output
+is safe to paste into a bug report.`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&flagDoctorJSON, "json", false, "Print results as JSON")
+	doctorCmd.Flags().BoolVar(&flagDoctorFix, "fix", false, "Remove expired and corrupt cache entries")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var checks []doctor.Check
+
+	p, err := config.WritePath()
+	if err != nil {
+		checks = append(checks, doctor.Check{Name: "Config file", Status: doctor.Fail, Detail: err.Error()})
+	} else {
+		checks = append(checks, doctor.ConfigFile(p))
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		checks = append(checks, doctor.Check{Name: "Effective config", Status: doctor.Fail, Detail: err.Error()})
+		cfg = &config.Config{}
+	}
+	src, err := cfg.KeySource()
+	keyCheck, key := doctor.KeySource(ctx, src, err)
+	checks = append(checks, keyCheck)
+	g, pc, err := newAPIClients(cfg, apikey.Static(key), modeDirect)
+	if err != nil {
+		checks = append(checks, doctor.Check{Name: "HTTP settings", Status: doctor.Fail, Detail: err.Error(),
+			Hint: "check the http, geocoding, and pollen sections of the config"})
+	} else {
+		checks = append(checks, doctor.APIs(ctx, key, g, pc)...)
+	}
+
+	c := cache.New("")
+	if flagDoctorFix {
+		if n, err := c.Prune(); err == nil && n > 0 && !flagDoctorJSON {
+			fmt.Printf("  Removed %d expired or corrupt cache entries\n", n)
+		}
+	}
+	checks = append(checks, doctor.CacheDir(c), doctor.CacheEntries(c))
+	clock, err := newHTTPClient(cfg, "pollen", modeProbe)
+	if err != nil {
+		clock = http.DefaultClient
+	}
+	checks = append(checks, doctor.Clock(ctx, clock, clockURL(cfg)))
+
+	if flagDoctorJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err := enc.Encode(struct {
+			OK     bool           `json:"ok"`
+			Checks []doctor.Check `json:"checks"`
+		}{!doctor.Failed(checks), checks})
+		if err != nil {
+			return err
+		}
+	} else {
+		ui.RenderDoctor(checks)
+	}
+
+	if doctor.Failed(checks) {
+		return errors.New("doctor found problems")
+	}
+	return nil
+}
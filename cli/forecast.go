@@ -1,21 +1,24 @@
 package cli
 
 import (
-	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/shunito/pollenow/internal/apikey"
+	"github.com/shunito/pollenow/internal/baseline"
 	"github.com/shunito/pollenow/internal/cache"
 	"github.com/shunito/pollenow/internal/config"
 	"github.com/shunito/pollenow/internal/forecast"
+	"github.com/shunito/pollenow/internal/format"
 	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/history"
 	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/timezone"
 	"github.com/shunito/pollenow/internal/ui"
 )
 
@@ -23,6 +26,7 @@ var (
 	flagDays    int
 	flagToday   bool
 	flagCompact bool
+	flagFormat  string
 )
 
 var forecastCmd = &cobra.Command{
@@ -37,6 +41,7 @@ func addForecastFlags(cmd *cobra.Command) {
 	cmd.Flags().IntVarP(&flagDays, "days", "d", 0, "Number of forecast days (1-5)")
 	cmd.Flags().BoolVarP(&flagToday, "today", "t", false, "Show today only (shortcut for -d 1)")
 	cmd.Flags().BoolVarP(&flagCompact, "compact", "c", false, "One-line summary output")
+	cmd.Flags().StringVarP(&flagFormat, "format", "f", "", "Go template for the output, or the name of one in the formats config")
 }
 
 func init() {
@@ -51,9 +56,11 @@ func runForecast(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// First-run: no config file exists
-	if !config.Exists() {
-		cfg, err = runFirstTimeSetup()
+	// First-run: no config file exists and no other layer supplies a key.
+	// Only prompt on a terminal; in CI or a container fall through to the
+	// missing-key error instead of waiting on stdin.
+	if !offline() && !config.Exists() && cfg.Validate() != nil && stdinIsTerminal() {
+		cfg, err = runSetup(setupOptions{Interactive: true})
 		if err != nil {
 			ui.RenderError(err)
 			return err
@@ -61,18 +68,21 @@ func runForecast(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate API key
-	if err := cfg.Validate(); err != nil {
-		ui.RenderError(fmt.Errorf("%w\nRun: pollenow config set api_key YOUR_KEY\nOr set POLLENOW_API_KEY environment variable", err))
+	if err := validateKey(cfg); err != nil {
+		ui.RenderError(fmt.Errorf("%w\n%s", err, messages.T("error.key_hint")))
 		return err
 	}
 
 	// Resolve ZIP code: arg > config default
 	zip := cfg.DefaultZIP
+	if zip == "" && flagDemo != "" {
+		zip = demoZIP
+	}
 	if len(args) > 0 {
 		zip = args[0]
 	}
 	if zip == "" {
-		err := fmt.Errorf("no ZIP code provided\nUsage: pollenow [ZIP]\nOr set a default: pollenow config set default_zip 94025")
+		err := errors.New(messages.T("error.no_zip", cmd.CommandPath()))
 		ui.RenderError(err)
 		return err
 	}
@@ -89,68 +99,135 @@ func runForecast(cmd *cobra.Command, args []string) error {
 		days = 1
 	}
 
-	// Create services
-	geocoder := geocoding.NewGoogleGeocoder(cfg.APIKey)
-	pollenClient := pollen.NewGooglePollenClient(cfg.APIKey)
-	c := cache.New("")
-	svc := forecast.NewService(geocoder, pollenClient, c)
-
-	// Fetch forecast with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	result, err := svc.GetForecast(ctx, zip, days)
-	if err != nil {
-		if errors.Is(err, geocoding.ErrInvalidZIP) {
-			ui.RenderError(fmt.Errorf("invalid ZIP code %q — please enter a 5-digit US ZIP code", zip))
-		} else if errors.Is(err, pollen.ErrInvalidDays) {
-			ui.RenderError(fmt.Errorf("days must be between 1 and 5"))
-		} else {
+	// Parse --format before fetching, so a typo doesn't cost a request
+	var tmpl *format.Template
+	if flagFormat != "" {
+		if tmpl, err = parseFormat(cfg, flagFormat); err != nil {
 			ui.RenderError(err)
+			return err
 		}
+	}
+
+	result, err := fetchForecast(cfg, zip, days)
+	if err != nil {
 		return err
 	}
 
 	// Render output
-	if flagCompact {
+	if tmpl != nil {
+		if err := tmpl.Execute(os.Stdout, result); err != nil {
+			ui.RenderError(err)
+			return err
+		}
+	} else if flagCompact || cfg.Output.Compact {
 		ui.RenderCompact(result)
 	} else {
-		ui.RenderForecast(result)
+		ui.RenderForecast(result, loadBaseline(zip, result))
 	}
 
 	return nil
 }
 
-// runFirstTimeSetup runs the interactive guided setup.
-func runFirstTimeSetup() (*config.Config, error) {
-	reader := bufio.NewReader(os.Stdin)
+// demoZIP is used in demo mode when no ZIP is given or configured.
+const demoZIP = "94025"
+
+// parseFormat resolves --format: the name of a template in the formats
+// section, or the template itself.
+func parseFormat(cfg *config.Config, spec string) (*format.Template, error) {
+	name, text := "format", spec
+	if t, ok := cfg.Formats[spec]; ok {
+		name, text = spec, t
+	}
+	return format.Parse(name, text, format.Options{
+		Thresholds: cfg.PollenThresholds(),
+		Messages:   messages,
+		Color:      ui.Colorize,
+	})
+}
+
+// validateKey checks that cfg names an API key. Offline runs need none.
+func validateKey(cfg *config.Config) error {
+	if offline() {
+		return nil
+	}
+	return cfg.Validate()
+}
 
-	fmt.Println()
-	fmt.Println("  Welcome to PolleNow! Let's get you set up.")
-	fmt.Println()
+// keySource returns where API requests get their key. Offline runs use a
+// placeholder.
+func keySource(cfg *config.Config) (apikey.Source, error) {
+	if offline() {
+		return apikey.Static("offline"), nil
+	}
+	return cfg.KeySource()
+}
 
-	fmt.Print("  Enter your Google API key: ")
-	apiKey, _ := reader.ReadString('\n')
-	apiKey = strings.TrimSpace(apiKey)
-	if apiKey == "" {
-		return nil, fmt.Errorf("API key is required")
+// fetchForecast builds the forecast service, fetches the forecast, and
+// records fresh results in the local history. Errors are rendered before
+// being returned.
+func fetchForecast(cfg *config.Config, zip string, days int) (*forecast.Result, error) {
+	// Create services. The key is resolved on the first request, so a
+	// cached answer never runs api_key_command or reads api_key_file.
+	src, err := keySource(cfg)
+	if err != nil {
+		ui.RenderError(err)
+		return nil, err
+	}
+	geocoder, pollenClient, err := newAPIClients(cfg, apikey.Once(src), modeManaged)
+	if err != nil {
+		ui.RenderError(err)
+		return nil, err
+	}
+	// Sample and replayed data must never end up in the real cache or
+	// history, and a recording needs the upstream exchange, not a cache hit.
+	var c *cache.Cache
+	if !offline() && os.Getenv(envRecord) == "" {
+		c = cache.New("").WithLogger(logger)
+	}
+	svc := forecast.NewService(geocoder, pollenClient, c).
+		WithLanguage(language(cfg)).
+		WithLogger(logger)
+	if cfg.TimeZone != "" {
+		svc.WithTimeZones(timezone.Fixed(cfg.TimeZone))
 	}
 
-	fmt.Print("  Enter your default ZIP code (optional): ")
-	zip, _ := reader.ReadString('\n')
-	zip = strings.TrimSpace(zip)
+	// Fetch forecast with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
 
-	cfg := &config.Config{
-		APIKey:     apiKey,
-		DefaultZIP: zip,
-		Days:       config.DefaultDays,
+	result, err := svc.GetForecast(ctx, zip, days)
+	if err != nil {
+		if errors.Is(err, geocoding.ErrInvalidZIP) {
+			ui.RenderError(errors.New(messages.T("error.invalid_zip", zip)))
+		} else if errors.Is(err, pollen.ErrInvalidDays) {
+			ui.RenderError(errors.New(messages.T("error.days")))
+		} else {
+			ui.RenderError(err)
+		}
+		return nil, err
 	}
 
-	if err := config.Save(cfg); err != nil {
-		return nil, fmt.Errorf("saving config: %w", err)
+	// Record fresh results so reports have history to draw on
+	if !result.Cached && !offline() {
+		_ = history.New("").Record(zip, result.Location, result.Forecast)
 	}
 
-	fmt.Printf("\n  ✓ Config saved to %s\n\n", config.Path())
+	return result, nil
+}
 
-	return cfg, nil
+// loadBaseline computes the seasonal norms for zip from local history.
+// It returns nil when there is no usable history.
+func loadBaseline(zip string, result *forecast.Result) *baseline.Baseline {
+	if len(result.Forecast.Days) == 0 || offline() {
+		return nil
+	}
+	records, err := history.New("").All(zip)
+	if err != nil || len(records) == 0 {
+		return nil
+	}
+	cutoff, err := time.Parse(pollen.DateLayout, result.Forecast.Days[0].Date)
+	if err != nil {
+		return nil
+	}
+	return baseline.Compute(records, cutoff)
 }
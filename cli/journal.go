@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shunito/pollenow/internal/journal"
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/ui"
+)
+
+var (
+	flagJournalDate     string
+	flagJournalSeverity int
+	flagJournalNote     string
+	flagJournalFrom     string
+	flagJournalTo       string
+)
+
+var journalCmd = &cobra.Command{
+	Use:   "journal",
+	Short: "Log symptoms and medications",
+	Long:  "Record symptoms and medications so they can be included in reports.",
+	RunE:  runJournalList,
+}
+
+var journalAddCmd = &cobra.Command{
+	Use:   "add <symptom|medication> <name>",
+	Short: "Add a journal entry",
+	Long: `Add a journal entry for today (or --date).
+
+Examples:
+  pollenow journal add symptom sneezing --severity 3
+  pollenow journal add medication cetirizine`,
+	Args: cobra.ExactArgs(2),
+	RunE: runJournalAdd,
+}
+
+var journalListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List journal entries",
+	RunE:  runJournalList,
+}
+
+func init() {
+	journalAddCmd.Flags().StringVar(&flagJournalDate, "date", "", "Entry date, YYYY-MM-DD (default: today)")
+	journalAddCmd.Flags().IntVarP(&flagJournalSeverity, "severity", "s", 0, "Symptom severity (1-5)")
+	journalAddCmd.Flags().StringVarP(&flagJournalNote, "note", "n", "", "Free-form note")
+
+	for _, c := range []*cobra.Command{journalCmd, journalListCmd} {
+		c.Flags().StringVar(&flagJournalFrom, "from", "", "Start date, YYYY-MM-DD (default: 7 days ago)")
+		c.Flags().StringVar(&flagJournalTo, "to", "", "End date, YYYY-MM-DD (default: today)")
+	}
+
+	journalCmd.AddCommand(journalAddCmd)
+	journalCmd.AddCommand(journalListCmd)
+}
+
+func runJournalAdd(cmd *cobra.Command, args []string) error {
+	date, err := parseDate(flagJournalDate, time.Now())
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	entry := journal.Entry{
+		Date:     date.Format(pollen.DateLayout),
+		Kind:     journal.Kind(strings.ToLower(args[0])),
+		Name:     args[1],
+		Severity: flagJournalSeverity,
+		Note:     flagJournalNote,
+	}
+
+	if err := journal.New("").Add(entry); err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	fmt.Printf("  ✓ Logged %s %q for %s\n", entry.Kind, entry.Name, entry.Date)
+	return nil
+}
+
+func runJournalList(cmd *cobra.Command, args []string) error {
+	from, to, err := parseDateRange(flagJournalFrom, flagJournalTo, 7)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	entries, err := journal.New("").Range(from, to)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("  No journal entries.")
+		return nil
+	}
+
+	for _, e := range entries {
+		line := fmt.Sprintf("  %s  %-10s %s", e.Date, e.Kind, e.Name)
+		if e.Severity > 0 {
+			line += fmt.Sprintf(" (%d)", e.Severity)
+		}
+		if e.Note != "" {
+			line += " — " + e.Note
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"github.com/shunito/pollenow/internal/apikey"
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/heatmap"
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/ui"
+)
+
+var (
+	flagMapType   string
+	flagMapZoom   int
+	flagMapRadius int
+	flagMapRender string
+	flagMapWidth  int
+	flagMapOutput string
+)
+
+var mapCmd = &cobra.Command{
+	Use:   "map [ZIP | LAT,LNG]",
+	Short: "Show a pollen heatmap around a location",
+	Long: `Fetch the Pollen API heatmap tiles around a location, stitch them together,
+and draw them in the terminal with the location marked.
+
+The map is drawn with the kitty graphics protocol or sixels when the terminal
+looks like it supports them, and with colored half-block characters otherwise.
+Use --render to choose, and --output to also save the map as a PNG.
+
+Each tile is one Pollen API request: the default radius of 1 fetches a 3x3
+grid, or 9 requests.`,
+	Example: `  pollenow map
+  pollenow map 10001 --type grass --zoom 10
+  pollenow map 47.61,-122.33 --output seattle.png`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMap,
+}
+
+func init() {
+	mapCmd.Flags().StringVar(&flagMapType, "type", "tree", "Pollen type: tree, grass, or weed")
+	mapCmd.Flags().IntVar(&flagMapZoom, "zoom", 8, fmt.Sprintf("Zoom level (0-%d); 8 shows about 150 km per tile", pollen.MaxZoom))
+	mapCmd.Flags().IntVar(&flagMapRadius, "radius", 1, fmt.Sprintf("Tiles to fetch on each side of the location (0-%d)", heatmap.MaxRadius))
+	mapCmd.Flags().StringVar(&flagMapRender, "render", heatmap.ProtocolAuto, "How to draw the map: auto, blocks, kitty, or sixel")
+	mapCmd.Flags().IntVar(&flagMapWidth, "width", 0, "Map width in terminal columns (default: fit the terminal)")
+	mapCmd.Flags().StringVarP(&flagMapOutput, "output", "o", "", "Also save the stitched map as a PNG file")
+}
+
+func runMap(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	mapType, err := pollen.MapType(flagMapType)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+	if err := heatmap.CheckBounds(flagMapZoom, flagMapRadius); err != nil {
+		ui.RenderError(err)
+		return err
+	}
+	if err := heatmap.CheckProtocol(flagMapRender); err != nil {
+		ui.RenderError(err)
+		return err
+	}
+	protocol := flagMapRender
+	if protocol == heatmap.ProtocolAuto {
+		protocol = heatmap.Detect(os.Getenv)
+	}
+
+	if err := validateKey(cfg); err != nil {
+		ui.RenderError(fmt.Errorf("%w\n%s", err, messages.T("error.key_hint")))
+		return err
+	}
+
+	location := cfg.DefaultZIP
+	if location == "" && flagDemo != "" {
+		location = demoZIP
+	}
+	if len(args) > 0 {
+		location = args[0]
+	}
+	if location == "" {
+		err := errors.New(messages.T("map.no_location"))
+		ui.RenderError(err)
+		return err
+	}
+
+	src, err := keySource(cfg)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+	geocoder, pollenClient, err := newAPIClients(cfg, apikey.Once(src), modeManaged)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	name, lat, lng, err := resolveLocation(ctx, geocoder, location)
+	if err != nil {
+		if errors.Is(err, geocoding.ErrInvalidZIP) {
+			err = errors.New(messages.T("map.bad_location", location))
+		}
+		ui.RenderError(err)
+		return err
+	}
+
+	m, err := heatmap.Stitch(ctx, pollenClient, mapType, lat, lng, flagMapZoom, flagMapRadius)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	if flagMapOutput != "" {
+		if err := m.SavePNG(flagMapOutput); err != nil {
+			ui.RenderError(err)
+			return err
+		}
+	}
+
+	if err := ui.RenderMap(name, flagMapType, flagMapZoom, m, protocol, mapWidth()); err != nil {
+		ui.RenderError(err)
+		return err
+	}
+	if flagMapOutput != "" {
+		fmt.Println(messages.T("map.saved", flagMapOutput))
+	}
+	return nil
+}
+
+// resolveLocation accepts "LAT,LNG" as is and geocodes anything else as a
+// ZIP code.
+func resolveLocation(ctx context.Context, g geocoding.Geocoder, location string) (name string, lat, lng float64, err error) {
+	if a, b, ok := strings.Cut(location, ","); ok {
+		lat, errLat := strconv.ParseFloat(strings.TrimSpace(a), 64)
+		lng, errLng := strconv.ParseFloat(strings.TrimSpace(b), 64)
+		if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			return "", 0, 0, fmt.Errorf("invalid coordinates %q — use LAT,LNG in degrees, e.g. 37.45,-122.18", location)
+		}
+		return fmt.Sprintf("%.4f, %.4f", lat, lng), lat, lng, nil
+	}
+	loc, err := g.Geocode(ctx, location)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return loc.DisplayName, loc.Lat, loc.Lng, nil
+}
+
+// mapWidth returns --width, or a width that fits the terminal with room
+// for the title and legend. Half-block rows are two pixels tall, so a
+// square map needs half as many rows as columns.
+func mapWidth() int {
+	if flagMapWidth > 0 {
+		return flagMapWidth
+	}
+	w, h, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || w <= 0 || h <= 0 {
+		return 64
+	}
+	return max(16, min(w-2, 2*(h-6)))
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/journal"
+	"github.com/shunito/pollenow/internal/plan"
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/ui"
+)
+
+var flagPlanTaken string
+
+var planCmd = &cobra.Command{
+	Use:   "plan [ZIP]",
+	Short: "Plan medication around the forecast",
+	Long: `Check the forecast against the medications in your config and say when to
+pre-medicate, e.g. "Take cetirizine tonight — tomorrow Tree is HIGH".
+
+Configure medications in config.yaml:
+
+  medications:
+    - name: cetirizine
+      targets: [tree, grass]
+      lead_time: 12h
+
+Record a dose with --taken so adherence shows up in the journal and reports.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPlan,
+}
+
+func init() {
+	planCmd.Flags().StringVar(&flagPlanTaken, "taken", "", "Record that you took this medication today")
+}
+
+func runPlan(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	j := journal.New("")
+	now := time.Now()
+
+	if flagPlanTaken != "" {
+		entry := journal.Entry{
+			Date: now.Format(pollen.DateLayout),
+			Kind: journal.KindMedication,
+			Name: flagPlanTaken,
+			Note: "pre-medicated (plan)",
+		}
+		if err := j.Add(entry); err != nil {
+			ui.RenderError(err)
+			return err
+		}
+		fmt.Printf("  ✓ Logged %s for %s\n", entry.Name, entry.Date)
+		return nil
+	}
+
+	if len(cfg.Medications) == 0 {
+		err := fmt.Errorf("no medications configured\nAdd a medications section to your config file (see: pollenow plan --help)")
+		ui.RenderError(err)
+		return err
+	}
+
+	if err := validateKey(cfg); err != nil {
+		ui.RenderError(fmt.Errorf("%w\n%s", err, messages.T("error.key_hint")))
+		return err
+	}
+
+	zip := cfg.DefaultZIP
+	if zip == "" && flagDemo != "" {
+		zip = demoZIP
+	}
+	if len(args) > 0 {
+		zip = args[0]
+	}
+	if zip == "" {
+		err := errors.New(messages.T("error.no_zip", cmd.CommandPath()))
+		ui.RenderError(err)
+		return err
+	}
+
+	result, err := fetchForecast(cfg, zip, config.DefaultDays)
+	if err != nil {
+		return err
+	}
+
+	advice, err := plan.Build(cfg.Medications, result.Forecast, cfg.PollenThresholds(), now)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	entries, err := j.Range(now.AddDate(0, 0, -1), now.AddDate(0, 0, len(result.Forecast.Days)))
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+	plan.ApplyJournal(advice, entries)
+
+	ui.RenderPlan(result, advice, now)
+	return nil
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/history"
+	"github.com/shunito/pollenow/internal/journal"
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/report"
+	"github.com/shunito/pollenow/internal/ui"
+)
+
+var (
+	flagReportFrom   string
+	flagReportTo     string
+	flagReportFormat string
+	flagReportOutput string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report [ZIP]",
+	Short: "Export a pollen and symptom report",
+	Long: `Export a date-range report combining recorded pollen levels, in-season plants,
+and journal entries, in a layout suitable for sharing with a doctor.
+
+Pollen history is recorded every time a forecast is fetched. Symptoms and
+medications come from the journal (see: pollenow journal add).`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&flagReportFrom, "from", "", "Start date, YYYY-MM-DD (default: 30 days ago)")
+	reportCmd.Flags().StringVar(&flagReportTo, "to", "", "End date, YYYY-MM-DD (default: today)")
+	reportCmd.Flags().StringVarP(&flagReportFormat, "format", "f", "html", "Output format: html, md, csv")
+	reportCmd.Flags().StringVarP(&flagReportOutput, "output", "o", "", "Write to file instead of stdout")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	zip := cfg.DefaultZIP
+	if len(args) > 0 {
+		zip = args[0]
+	}
+	if zip == "" {
+		err := errors.New(messages.T("error.no_zip", cmd.CommandPath()))
+		ui.RenderError(err)
+		return err
+	}
+	if !geocoding.ValidZIP(zip) {
+		err := fmt.Errorf("%w: %q — use a 5-digit US ZIP code", geocoding.ErrInvalidZIP, zip)
+		ui.RenderError(err)
+		return err
+	}
+
+	format, err := report.ParseFormat(flagReportFormat)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	from, to, err := parseDateRange(flagReportFrom, flagReportTo, 30)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	records, err := history.New("").Range(zip, from, to)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+	entries, err := journal.New("").Range(from, to)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	r := report.Build(zip, records, entries, from, to, cfg.PollenThresholds())
+	if flagReportOutput == "" {
+		if err := r.Render(os.Stdout, format); err != nil {
+			ui.RenderError(fmt.Errorf("rendering report: %w", err))
+			return err
+		}
+		return nil
+	}
+
+	f, err := os.Create(flagReportOutput)
+	if err != nil {
+		ui.RenderError(fmt.Errorf("creating report file: %w", err))
+		return err
+	}
+	err = r.Render(f, format)
+	if cerr := f.Close(); err == nil && cerr != nil {
+		ui.RenderError(fmt.Errorf("saving report file: %w", cerr))
+		return cerr
+	}
+	if err != nil {
+		ui.RenderError(fmt.Errorf("rendering report: %w", err))
+		return err
+	}
+
+	fmt.Printf("  ✓ Report saved to %s\n", flagReportOutput)
+	return nil
+}
+
+// parseDateRange parses --from/--to flags. An empty to means today and an
+// empty from means defaultDays before to.
+func parseDateRange(fromStr, toStr string, defaultDays int) (time.Time, time.Time, error) {
+	to, err := parseDate(toStr, time.Now())
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	from, err := parseDate(fromStr, to.AddDate(0, 0, -defaultDays))
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from %s is after --to %s", from.Format(pollen.DateLayout), to.Format(pollen.DateLayout))
+	}
+	return from, to, nil
+}
+
+// parseDate parses a YYYY-MM-DD date, returning def's date when s is empty.
+func parseDate(s string, def time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Date(def.Year(), def.Month(), def.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	t, err := time.Parse(pollen.DateLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q — use YYYY-MM-DD", s)
+	}
+	return t, nil
+}
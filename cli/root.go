@@ -1,12 +1,50 @@
 package cli
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/i18n"
+	"github.com/shunito/pollenow/internal/logging"
+	"github.com/shunito/pollenow/internal/theme"
+	"github.com/shunito/pollenow/internal/tracing"
+	"github.com/shunito/pollenow/internal/ui"
+	"github.com/shunito/pollenow/internal/usage"
+	"github.com/shunito/pollenow/internal/xdg"
 )
 
 var (
 	// Version is set at build time via ldflags.
 	Version = "dev"
+
+	flagVerbose   int
+	flagLogFormat string
+	flagLogFile   string
+	flagLang      string
+	flagTheme     string
+	flagColor     string
+	flagASCII     bool
+	flagAPIColors bool
+
+	// messages translates the CLI's own text. It is English until the
+	// language setting is known.
+	messages = i18n.English
+
+	// logger receives debug output from the services and API clients.
+	// It discards everything until the root command's flags are parsed.
+	logger = logging.Discard
+
+	// closeLog releases the --log-file handle before exit.
+	closeLog = func() error { return nil }
+
+	// shutdownTracing flushes spans before exit. Tracing starts disabled.
+	shutdownTracing = func(context.Context) error { return nil }
 )
 
 var rootCmd = &cobra.Command{
@@ -18,18 +56,170 @@ var rootCmd = &cobra.Command{
 	SilenceErrors: true,
 	SilenceUsage:  true,
 	RunE:          runForecast,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupLogging(); err != nil {
+			ui.RenderError(err)
+			return err
+		}
+		if flagLang != "" && !i18n.Valid(flagLang) {
+			err := fmt.Errorf("invalid --lang %q: must be a language tag such as en, ja, or pt-BR", flagLang)
+			ui.RenderError(err)
+			return err
+		}
+		migrateLegacyDirs()
+
+		// A config that fails to load is left for the command to report;
+		// until then, flags alone decide the language and appearance.
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = &config.Config{}
+		}
+		if err := setupOutput(cfg); err != nil {
+			ui.RenderError(err)
+			return err
+		}
+		setupTracing(cfg.Tracing)
+		return nil
+	},
 }
 
 func init() {
 	// Register forecast flags on the root command so `pollenow -d 3` works
 	addForecastFlags(rootCmd)
 
+	rootCmd.PersistentFlags().StringVar(&config.OverridePath, "config", "", "Config file layered over all other settings")
+	rootCmd.PersistentFlags().StringVar(&config.ProfileOverride, "profile", "", "Config profile to use (overrides POLLENOW_PROFILE)")
+	rootCmd.PersistentFlags().CountVarP(&flagVerbose, "verbose", "v", "Log requests and retries (-v) or also cache and service detail (-vv)")
+	rootCmd.PersistentFlags().StringVar(&flagLogFormat, "log-format", logging.FormatText, "Log format: text or json")
+	rootCmd.PersistentFlags().StringVar(&flagLogFile, "log-file", "", "Append logs to this file instead of stderr")
+	rootCmd.PersistentFlags().StringVar(&flagLang, "lang", "", "Language for forecasts and messages, e.g. ja or es (overrides the language setting)")
+	rootCmd.PersistentFlags().StringVar(&flagTheme, "theme", "", "Color theme: "+strings.Join(theme.Names(), ", "))
+	rootCmd.PersistentFlags().StringVar(&flagColor, "color", "", "When to use color: auto, always, or never (default auto, off when NO_COLOR is set)")
+	rootCmd.PersistentFlags().Lookup("color").NoOptDefVal = theme.ColorAlways
+	rootCmd.PersistentFlags().BoolVar(&flagASCII, "ascii", false, "Draw with ASCII instead of emoji and box-drawing characters")
+	rootCmd.PersistentFlags().BoolVar(&flagAPIColors, "api-colors", false, "Color pollen levels with the Pollen API's own colors")
+	rootCmd.PersistentFlags().StringVar(&flagDemo, "demo", "", "Use built-in sample data instead of Google APIs (see mock-server for scenarios)")
+	rootCmd.PersistentFlags().Lookup("demo").NoOptDefVal = "normal"
+
 	rootCmd.AddCommand(forecastCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(journalCmd)
+	rootCmd.AddCommand(mapCmd)
+	rootCmd.AddCommand(mockServerCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(seasonCmd)
+	rootCmd.AddCommand(usageCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
+// setupLogging builds the logger from the -v, --log-format, and --log-file
+// flags. A log file without -v still records requests and retries, since
+// asking for a file means asking for a trail.
+func setupLogging() error {
+	verbosity := flagVerbose
+	if flagLogFile != "" && verbosity == 0 {
+		verbosity = 1
+	}
+	l, closeFn, err := logging.New(logging.Options{
+		Verbosity: verbosity,
+		Format:    flagLogFormat,
+		File:      flagLogFile,
+	}, os.Stderr)
+	if err != nil {
+		return err
+	}
+	logger, closeLog = l, closeFn
+	return nil
+}
+
+// setupOutput picks the message language and hands the CLI's choices to
+// ui: the thresholds, and the theme, color, and glyph settings, flags
+// first, then the output section of the config.
+func setupOutput(cfg *config.Config) error {
+	messages = i18n.New(language(cfg))
+
+	out := cfg.Output
+	mode := flagColor
+	if mode == "" {
+		mode = out.Color
+	}
+	if mode == "" {
+		mode = theme.ColorAuto
+	}
+	if !theme.ValidColorMode(mode) {
+		return fmt.Errorf("invalid --color %q: must be auto, always, or never", mode)
+	}
+	name := flagTheme
+	if name == "" {
+		name = out.Theme
+	}
+	t, err := theme.Get(name)
+	if err != nil {
+		return err
+	}
+
+	ui.Configure(ui.Settings{
+		Appearance: ui.Appearance{
+			Theme:     t,
+			ASCII:     flagASCII || out.ASCII,
+			APIColors: flagAPIColors || out.APIColors,
+		},
+		ColorMode:  mode,
+		Thresholds: cfg.PollenThresholds(),
+		Messages:   messages,
+	})
+	return nil
+}
+
+// setupTracing starts OpenTelemetry export when the tracing section asks
+// for it. An exporter that fails to start only costs the traces.
+func setupTracing(tc config.Tracing) {
+	shutdown, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter: tc.Exporter,
+		Endpoint: tc.Endpoint,
+		Version:  Version,
+		Log:      logger,
+	})
+	if err != nil {
+		logger.Warn("tracing disabled", "err", err)
+		return
+	}
+	shutdownTracing = shutdown
+}
+
+// migrateLegacyDirs moves files from the pre-XDG locations into the
+// directories selected by the XDG variables, then moves the usage ledger
+// from the data directory to the state directory.
+func migrateLegacyDirs() {
+	moved, err := xdg.MigrateLegacy()
+	if err == nil {
+		var m string
+		m, err = usage.MigrateLedger()
+		if m != "" {
+			moved = append(moved, m)
+		}
+	}
+	for _, m := range moved {
+		fmt.Fprintf(os.Stderr, "  pollenow: %s\n", m)
+	}
+	if err != nil {
+		ui.RenderError(err)
+	}
+}
+
 // Execute runs the root command.
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if serr := shutdownTracing(ctx); serr != nil {
+		logger.Warn("flushing traces failed", "err", serr)
+	}
+	if cerr := closeLog(); cerr != nil {
+		fmt.Fprintf(os.Stderr, "pollenow: closing log file: %v\n", cerr)
+	}
+	return err
 }
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/history"
+	"github.com/shunito/pollenow/internal/season"
+	"github.com/shunito/pollenow/internal/ui"
+)
+
+var (
+	flagSeasonYears  int
+	flagSeasonPlants bool
+)
+
+var seasonCmd = &cobra.Command{
+	Use:   "season [ZIP]",
+	Short: "Show pollen season calendar",
+	Long: `Show when each pollen season started and ended for a location, as a
+calendar chart of the current and past years.
+
+Seasons are detected from the forecast history recorded each time you run
+pollenow, so the chart fills in as history accumulates.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSeason,
+}
+
+func init() {
+	seasonCmd.Flags().IntVarP(&flagSeasonYears, "years", "y", 3, "Number of years to show")
+	seasonCmd.Flags().BoolVarP(&flagSeasonPlants, "plants", "p", false, "Include individual plant species")
+}
+
+func runSeason(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	zip := cfg.DefaultZIP
+	if len(args) > 0 {
+		zip = args[0]
+	}
+	if zip == "" {
+		err := errors.New(messages.T("error.no_zip", cmd.CommandPath()))
+		ui.RenderError(err)
+		return err
+	}
+	if !geocoding.ValidZIP(zip) {
+		err := fmt.Errorf("%w: %q — use a 5-digit US ZIP code", geocoding.ErrInvalidZIP, zip)
+		ui.RenderError(err)
+		return err
+	}
+	if flagSeasonYears < 1 {
+		flagSeasonYears = 1
+	}
+
+	records, err := history.New("").All(zip)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	location := zip
+	if n := len(records); n > 0 && records[n-1].Location.DisplayName != "" {
+		location = records[n-1].Location.DisplayName
+	}
+
+	var seasons []season.Season
+	for _, s := range season.Detect(records, season.DefaultMaxGap) {
+		if s.Kind == season.KindPlant && !flagSeasonPlants {
+			continue
+		}
+		seasons = append(seasons, s)
+	}
+
+	now := time.Now()
+	years := make([]int, 0, flagSeasonYears)
+	for i := 0; i < flagSeasonYears; i++ {
+		years = append(years, now.Year()-i)
+	}
+
+	ui.RenderSeasons(location, seasons, years, now)
+	return nil
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+
+	"github.com/shunito/pollenow/internal/apikey"
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/keycheck"
+)
+
+// setupOptions controls runSetup. Values given here are used as-is;
+// missing ones are prompted for when Interactive is set.
+type setupOptions struct {
+	APIKey      string
+	ZIP         string
+	Interactive bool
+	SkipCheck   bool // save without checking the key against Google
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal.
+// Redirected input, including /dev/null, is not.
+func stdinIsTerminal() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// runSetup writes the API key and default ZIP code to the writable config
+// file, keeping any other settings already in it, and returns the
+// effective config.
+func runSetup(opts setupOptions) (*config.Config, error) {
+	p, err := config.WritePath()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader *bufio.Reader
+	if opts.Interactive {
+		reader = bufio.NewReader(os.Stdin)
+		fmt.Println()
+		fmt.Println("  Welcome to PolleNow! Let's get you set up.")
+		fmt.Println()
+	}
+
+	apiKey := opts.APIKey
+	if apiKey == "" && opts.Interactive {
+		apiKey = prompt(reader, "Enter your Google API key: ")
+	}
+	if apiKey == "" {
+		apiKey = os.Getenv(config.EnvVar("api_key"))
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is required — pass --api-key or set %s", config.EnvVar("api_key"))
+	}
+	if err := setField(cfg, "api_key", apiKey); err != nil {
+		return nil, err
+	}
+
+	zip := opts.ZIP
+	if zip == "" && opts.Interactive {
+		zip = prompt(reader, "Enter your default ZIP code (optional): ")
+	}
+	if zip != "" {
+		if err := setField(cfg, "default_zip", zip); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Days == 0 {
+		cfg.Days = config.DefaultDays
+	}
+
+	if !opts.SkipCheck && !checkKey(apiKey) {
+		if !opts.Interactive || !confirm(reader, "Save this key anyway? [y/N] ") {
+			return nil, errors.New("API key check failed — fix the problems above, or pass --skip-check to save the key anyway")
+		}
+	}
+
+	if err := config.Save(cfg); err != nil {
+		return nil, fmt.Errorf("saving config: %w", err)
+	}
+	fmt.Printf("\n  ✓ Config saved to %s\n\n", p)
+
+	return config.Load()
+}
+
+// checkKey makes a live request to each Google API the key needs and
+// prints the outcome. It reports whether every API accepted the key.
+func checkKey(apiKey string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	fmt.Println("  Checking API key...")
+	// Endpoint, proxy, and CA settings may come from any layer.
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	g, p, err := newAPIClients(cfg, apikey.Static(apiKey), modeDirect)
+	if err != nil {
+		fmt.Printf("  ✗ %v\n", err)
+		return false
+	}
+	results := keycheck.Check(ctx, g, p)
+	for _, r := range results {
+		if r.Status == keycheck.OK {
+			fmt.Printf("  ✓ %s\n", r.API)
+			continue
+		}
+		fmt.Printf("  ✗ %s: %s\n", r.API, r.Status)
+		if hint := r.Hint(); hint != "" {
+			fmt.Printf("      %s\n", hint)
+		} else if r.Err != nil {
+			fmt.Printf("      %v\n", r.Err)
+		}
+	}
+	return keycheck.AllOK(results)
+}
+
+// setField sets a schema key on cfg, validating the value.
+func setField(cfg *config.Config, key, value string) error {
+	f, err := config.Lookup(key)
+	if err != nil {
+		return err
+	}
+	return f.Set(cfg, value)
+}
+
+func prompt(reader *bufio.Reader, label string) string {
+	fmt.Print("  " + label)
+	s, _ := reader.ReadString('\n')
+	return strings.TrimSpace(s)
+}
+
+func confirm(reader *bufio.Reader, label string) bool {
+	answer := strings.ToLower(prompt(reader, label))
+	return answer == "y" || answer == "yes"
+}
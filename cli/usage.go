@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/ui"
+	"github.com/shunito/pollenow/internal/usage"
+)
+
+var flagUsageMonth string
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show Google API usage and estimated cost",
+	Long: `Show how many Geocoding and Pollen API requests PolleNow has made in a
+month, with failures, latency, and estimated cost.
+
+Set daily caps with:
+  pollenow config set usage.soft_daily_cap 100   # warn
+  pollenow config set usage.hard_daily_cap 500   # refuse further requests`,
+	Args: cobra.NoArgs,
+	RunE: runUsage,
+}
+
+func init() {
+	usageCmd.Flags().StringVarP(&flagUsageMonth, "month", "m", "", "Month to report, YYYY-MM (default: this month)")
+}
+
+func runUsage(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	now := time.Now()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	if flagUsageMonth != "" {
+		month, err = time.ParseInLocation("2006-01", flagUsageMonth, time.Local)
+		if err != nil {
+			err = fmt.Errorf("invalid month %q — use YYYY-MM", flagUsageMonth)
+			ui.RenderError(err)
+			return err
+		}
+	}
+
+	ledger := usage.New("")
+	records, err := ledger.Range(month, month.AddDate(0, 1, 0))
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+	today, err := ledger.Today(now)
+	if err != nil {
+		ui.RenderError(err)
+		return err
+	}
+
+	ui.RenderUsage(month, usage.Summarize(records), today, usageCaps(cfg))
+	return nil
+}
+
+func usageCaps(cfg *config.Config) usage.Caps {
+	return usage.Caps{Soft: cfg.Usage.SoftDailyCap, Hard: cfg.Usage.HardDailyCap}
+}
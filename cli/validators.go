@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/format"
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/i18n"
+	"github.com/shunito/pollenow/internal/theme"
+	"github.com/shunito/pollenow/internal/timezone"
+	"github.com/shunito/pollenow/internal/tracing"
+)
+
+// configValidators are the config checks that need packages config itself
+// does not import, keyed by validate tag.
+var configValidators = map[string]func(v any) error{
+	"zip": func(v any) error {
+		if !geocoding.ValidZIP(v.(string)) {
+			return errors.New("must be a 5-digit US ZIP code")
+		}
+		return nil
+	},
+	"language": func(v any) error {
+		if !i18n.Valid(v.(string)) {
+			return errors.New("must be a language tag such as en, ja, or pt-BR")
+		}
+		return nil
+	},
+	"timezone": func(v any) error {
+		if name := v.(string); name != "" {
+			if _, err := timezone.Load(name); err != nil {
+				return errors.New("must be an IANA time zone such as America/Chicago")
+			}
+		}
+		return nil
+	},
+	"exporter": func(v any) error {
+		if !tracing.ValidExporter(v.(string)) {
+			return errors.New("must be none, otlp, or stdout")
+		}
+		return nil
+	},
+	"theme": func(v any) error {
+		if !theme.Valid(v.(string)) {
+			return fmt.Errorf("must be one of %s", strings.Join(theme.Names(), ", "))
+		}
+		return nil
+	},
+	"color": func(v any) error {
+		if !theme.ValidColorMode(v.(string)) {
+			return errors.New("must be auto, always, or never")
+		}
+		return nil
+	},
+	"formats": func(v any) error {
+		formats := v.(map[string]string)
+		names := make([]string, 0, len(formats))
+		for name := range formats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := format.Check(name, formats[name]); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	for name, check := range configValidators {
+		config.RegisterValidator(name, check)
+	}
+}
@@ -0,0 +1,168 @@
+package apikey
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrNoKey    = errors.New("no API key configured")
+	ErrEmptyKey = errors.New("API key source returned an empty key")
+)
+
+// Header carries the API key on requests to Google, keeping it out of
+// URLs and therefore out of errors, proxy logs, and shell history.
+const Header = "X-Goog-Api-Key"
+
+// Source supplies the API key when a request needs it. Implementations
+// must never include the key itself in errors or descriptions.
+type Source interface {
+	Key(ctx context.Context) (string, error)
+	// Describe says where the key comes from, e.g. `command "pass show pollen"`.
+	Describe() string
+}
+
+// Static is a key given directly, from the config file or environment.
+type Static string
+
+// Key returns the key, or ErrNoKey if it is empty.
+func (s Static) Key(ctx context.Context) (string, error) {
+	if s == "" {
+		return "", ErrNoKey
+	}
+	return string(s), nil
+}
+
+// Describe implements Source.
+func (s Static) Describe() string {
+	return "inline"
+}
+
+// File reads the key from a file such as a Docker or Kubernetes secret.
+// Surrounding whitespace is ignored and a leading ~/ is expanded.
+type File string
+
+// Key reads and trims the file contents.
+func (f File) Key(ctx context.Context) (string, error) {
+	p, err := expandHome(string(f))
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		// *fs.PathError holds only the path and cause, never the contents.
+		return "", fmt.Errorf("reading api_key_file: %w", err)
+	}
+	key := strings.TrimSpace(string(data))
+	if key == "" {
+		return "", fmt.Errorf("%w: file %s", ErrEmptyKey, p)
+	}
+	return key, nil
+}
+
+// Describe implements Source.
+func (f File) Describe() string {
+	return fmt.Sprintf("file %s", string(f))
+}
+
+// Command runs a shell command and uses its trimmed stdout as the key,
+// e.g. "pass show google/pollen" or "op read op://dev/pollen/key".
+type Command string
+
+// Key runs the command. Stdout is never included in errors; stderr is,
+// since password managers report problems there.
+func (c Command) Key(ctx context.Context) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := shellCommand(ctx, string(c))
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	cmd.Stdin = os.Stdin // let the password manager prompt if it needs to
+
+	if err := cmd.Run(); err != nil {
+		msg := firstLine(stderr.String())
+		if out := strings.TrimSpace(stdout.String()); out != "" {
+			msg = strings.ReplaceAll(msg, out, "***")
+		}
+		if msg != "" {
+			return "", fmt.Errorf("api_key_command %q failed: %v: %s", string(c), err, msg)
+		}
+		return "", fmt.Errorf("api_key_command %q failed: %w", string(c), err)
+	}
+
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return "", fmt.Errorf("%w: command %q", ErrEmptyKey, string(c))
+	}
+	return key, nil
+}
+
+// Describe implements Source.
+func (c Command) Describe() string {
+	return fmt.Sprintf("command %q", string(c))
+}
+
+// Once wraps src so the key is resolved at most once, on first use. Both
+// API clients can share it without running a command twice, and nothing
+// runs at all when every answer comes from the cache.
+func Once(src Source) Source {
+	return &once{src: src}
+}
+
+type once struct {
+	src  Source
+	mu   sync.Mutex
+	done bool
+	key  string
+}
+
+func (o *once) Key(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.done {
+		return o.key, nil
+	}
+	key, err := o.src.Key(ctx)
+	if err != nil {
+		// Failures are not cached so a later call can retry.
+		return "", err
+	}
+	o.key, o.done = key, true
+	return key, nil
+}
+
+func (o *once) Describe() string {
+	return o.src.Describe()
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+func expandHome(p string) (string, error) {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("expanding %s: %w", p, err)
+	}
+	return filepath.Join(home, strings.TrimPrefix(p, "~")), nil
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
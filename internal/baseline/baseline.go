@@ -0,0 +1,145 @@
+package baseline
+
+import (
+	"time"
+
+	"github.com/shunito/pollenow/internal/history"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+const (
+	// window is how many weeks on either side of a date feed its norm.
+	window = 1
+	// minSamples is how many recorded days a norm needs before it is used.
+	minSamples = 3
+	// margin is how far (in UPI steps) a level must be from the norm to
+	// count as above or below typical.
+	margin = 1.0
+)
+
+// Comparison describes how a level compares with the seasonal norm.
+type Comparison int
+
+const (
+	Unknown Comparison = iota
+	Below
+	Typical
+	Above
+)
+
+type stat struct {
+	sum float64
+	n   int
+}
+
+// Baseline holds per-pollen-type sums by ISO week of year for one location.
+type Baseline struct {
+	weeks map[string]map[int]*stat // type code -> week -> stat
+}
+
+// Compute builds a baseline from recorded history. Records dated on or
+// after cutoff are ignored so a forecast is never compared with itself.
+func Compute(records []history.Record, cutoff time.Time) *Baseline {
+	b := &Baseline{weeks: make(map[string]map[int]*stat)}
+	limit := cutoff.Format(pollen.DateLayout)
+
+	for _, r := range records {
+		if r.Day.Date >= limit {
+			continue
+		}
+		date, err := time.Parse(pollen.DateLayout, r.Day.Date)
+		if err != nil {
+			continue
+		}
+		_, week := date.ISOWeek()
+		for _, tl := range r.Day.Types() {
+			if tl.Level.Level == nil {
+				continue
+			}
+			byWeek, ok := b.weeks[tl.Code]
+			if !ok {
+				byWeek = make(map[int]*stat)
+				b.weeks[tl.Code] = byWeek
+			}
+			s, ok := byWeek[week]
+			if !ok {
+				s = &stat{}
+				byWeek[week] = s
+			}
+			s.sum += float64(*tl.Level.Level)
+			s.n++
+		}
+	}
+
+	return b
+}
+
+// Norm returns the mean level for a pollen type around the given date's
+// week of year, pooling the neighbouring weeks. ok is false when fewer
+// than minSamples days are on record.
+func (b *Baseline) Norm(code string, date time.Time) (mean float64, n int, ok bool) {
+	if b == nil {
+		return 0, 0, false
+	}
+	byWeek := b.weeks[code]
+	_, week := date.ISOWeek()
+
+	var sum float64
+	for w := week - window; w <= week+window; w++ {
+		if s, found := byWeek[wrapWeek(w)]; found {
+			sum += s.sum
+			n += s.n
+		}
+	}
+	if n < minSamples {
+		return 0, n, false
+	}
+	return sum / float64(n), n, true
+}
+
+// Compare reports how a level compares with the norm for its type and date.
+func (b *Baseline) Compare(code string, date time.Time, l pollen.PollenLevel) Comparison {
+	if l.Level == nil {
+		return Unknown
+	}
+	mean, _, ok := b.Norm(code, date)
+	if !ok {
+		return Unknown
+	}
+
+	v := float64(*l.Level)
+	switch {
+	case v >= mean+margin:
+		return Above
+	case v <= mean-margin:
+		return Below
+	default:
+		return Typical
+	}
+}
+
+// CompareDay compares each pollen type of a forecast day with its norm,
+// keyed by type code. Unparseable dates yield an empty map.
+func (b *Baseline) CompareDay(day pollen.DayForecast) map[string]Comparison {
+	out := make(map[string]Comparison, 3)
+	date, err := time.Parse(pollen.DateLayout, day.Date)
+	if err != nil {
+		return out
+	}
+	for _, tl := range day.Types() {
+		out[tl.Code] = b.Compare(tl.Code, date, tl.Level)
+	}
+	return out
+}
+
+// wrapWeek maps week numbers outside 1-53 onto the neighbouring year.
+func wrapWeek(w int) int {
+	switch {
+	case w < 1:
+		return w + 53
+	case w > 53:
+		return w - 53
+	default:
+		return w
+	}
+}
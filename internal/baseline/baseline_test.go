@@ -0,0 +1,87 @@
+package baseline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shunito/pollenow/internal/history"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+func lvl(v int) pollen.PollenLevel {
+	return pollen.PollenLevel{Level: &v}
+}
+
+// aprilHistory returns tree readings around mid-April in past years.
+func aprilHistory() []history.Record {
+	var records []history.Record
+	for _, year := range []int{2023, 2024} {
+		for day := 10; day <= 20; day++ {
+			d := time.Date(year, 4, day, 0, 0, 0, 0, time.UTC)
+			records = append(records, history.Record{Day: pollen.DayForecast{
+				Date:  d.Format(pollen.DateLayout),
+				Tree:  lvl(3),
+				Grass: lvl(1),
+			}})
+		}
+	}
+	return records
+}
+
+func TestCompare(t *testing.T) {
+	b := Compute(aprilHistory(), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	date := time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)
+
+	mean, n, ok := b.Norm("TREE", date)
+	if !ok || mean != 3 || n < minSamples {
+		t.Fatalf("Norm: got mean %v n %d ok %v", mean, n, ok)
+	}
+
+	tests := []struct {
+		level int
+		want  Comparison
+	}{
+		{5, Above},
+		{4, Above},
+		{3, Typical},
+		{2, Below},
+	}
+	for _, tt := range tests {
+		if got := b.Compare("TREE", date, lvl(tt.level)); got != tt.want {
+			t.Errorf("Compare(TREE, %d): got %v, want %v", tt.level, got, tt.want)
+		}
+	}
+
+	if got := b.Compare("TREE", date, pollen.PollenLevel{Category: "No Data"}); got != Unknown {
+		t.Errorf("missing level: got %v, want Unknown", got)
+	}
+	if got := b.Compare("WEED", date, lvl(2)); got != Unknown {
+		t.Errorf("no weed history: got %v, want Unknown", got)
+	}
+	if got := b.Compare("TREE", time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), lvl(2)); got != Unknown {
+		t.Errorf("no history for September: got %v, want Unknown", got)
+	}
+}
+
+func TestComputeCutoff(t *testing.T) {
+	b := Compute(aprilHistory(), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	_, n, _ := b.Norm("TREE", time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC))
+	if n != 11 {
+		t.Errorf("samples before cutoff: got %d, want 11", n)
+	}
+}
+
+func TestCompareDay(t *testing.T) {
+	b := Compute(aprilHistory(), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	got := b.CompareDay(pollen.DayForecast{Date: "2025-04-15", Tree: lvl(5), Grass: lvl(1)})
+
+	if got["TREE"] != Above || got["GRASS"] != Typical || got["WEED"] != Unknown {
+		t.Errorf("CompareDay: got %v", got)
+	}
+}
+
+func TestWrapWeek(t *testing.T) {
+	if wrapWeek(0) != 53 || wrapWeek(54) != 1 || wrapWeek(20) != 20 {
+		t.Error("wrapWeek did not wrap around the year")
+	}
+}
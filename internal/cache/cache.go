@@ -3,60 +3,94 @@ package cache
 import (
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/shunito/pollenow/internal/logging"
+	"github.com/shunito/pollenow/internal/xdg"
 )
 
 const defaultTTL = 1 * time.Hour
 
 // Entry wraps cached data with a timestamp.
 type Entry struct {
-	Data      json.RawMessage `json:"data"`
-	CachedAt  time.Time       `json:"cachedAt"`
+	Data     json.RawMessage `json:"data"`
+	CachedAt time.Time       `json:"cachedAt"`
 }
 
 // Cache provides file-based caching with a TTL.
 type Cache struct {
 	dir string
 	ttl time.Duration
+	err error // set when no cache directory could be resolved
+	log *slog.Logger
 }
 
-// New creates a Cache. If dir is empty, uses ~/.cache/pollenow/.
+// New creates a Cache. If dir is empty, uses $XDG_CACHE_HOME/pollenow/.
+// If that cannot be resolved, the cache is disabled: Get always misses and
+// Set returns the resolution error.
 func New(dir string) *Cache {
+	c := &Cache{dir: dir, ttl: defaultTTL, log: logging.Discard}
 	if dir == "" {
-		home, _ := os.UserHomeDir()
-		dir = filepath.Join(home, ".cache", "pollenow")
+		c.dir, c.err = xdg.CacheDir()
+	}
+	return c
+}
+
+// WithLogger sets the logger for hits, misses, and writes. A nil l keeps
+// the current one.
+func (c *Cache) WithLogger(l *slog.Logger) *Cache {
+	if l != nil {
+		c.log = l
 	}
-	return &Cache{dir: dir, ttl: defaultTTL}
+	return c
+}
+
+// Dir returns the cache directory, or an error if it could not be resolved.
+func (c *Cache) Dir() (string, error) {
+	return c.dir, c.err
 }
 
 // Get retrieves a cached entry. Returns nil if not found or expired.
 // Also returns how long ago the entry was cached.
 func (c *Cache) Get(key string) (json.RawMessage, time.Duration, bool) {
+	if c.err != nil {
+		c.log.Debug("cache miss", "key", key, "reason", "disabled", "err", c.err)
+		return nil, 0, false
+	}
 	p := c.path(key)
 	data, err := os.ReadFile(p)
 	if err != nil {
+		c.log.Debug("cache miss", "key", key, "reason", "absent")
 		return nil, 0, false
 	}
 
 	var entry Entry
 	if err := json.Unmarshal(data, &entry); err != nil {
+		c.log.Debug("cache miss", "key", key, "reason", "corrupt", "err", err)
 		return nil, 0, false
 	}
 
 	age := time.Since(entry.CachedAt)
 	if age > c.ttl {
+		c.log.Debug("cache miss", "key", key, "reason", "expired", "age", age.Round(time.Second))
 		os.Remove(p)
 		return nil, 0, false
 	}
 
+	c.log.Debug("cache hit", "key", key, "age", age.Round(time.Second))
 	return entry.Data, age, true
 }
 
 // Set stores data in the cache.
 func (c *Cache) Set(key string, data any) error {
+	if c.err != nil {
+		return c.err
+	}
 	if err := os.MkdirAll(c.dir, 0o755); err != nil {
 		return err
 	}
@@ -76,13 +110,77 @@ func (c *Cache) Set(key string, data any) error {
 		return err
 	}
 
-	return os.WriteFile(c.path(key), out, 0o600)
+	if err := os.WriteFile(c.path(key), out, 0o600); err != nil {
+		return err
+	}
+	c.log.Debug("cache store", "key", key, "ttl", c.ttl)
+	return nil
+}
+
+// Stats summarizes the entries in the cache directory.
+type Stats struct {
+	Entries int // valid, unexpired entries
+	Expired int // entries past the TTL; refetched and overwritten on the next lookup of their key
+	Corrupt int // files that are not valid cache entries
+}
+
+// Inspect counts the entries in the cache directory without changing it.
+// A missing directory is an empty cache.
+func (c *Cache) Inspect() (Stats, error) {
+	var st Stats
+	err := c.scan(func(p string, valid, expired bool) {
+		switch {
+		case !valid:
+			st.Corrupt++
+		case expired:
+			st.Expired++
+		default:
+			st.Entries++
+		}
+	})
+	return st, err
+}
+
+// Prune removes expired and corrupt entries and returns how many it removed.
+func (c *Cache) Prune() (int, error) {
+	removed := 0
+	err := c.scan(func(p string, valid, expired bool) {
+		if (!valid || expired) && os.Remove(p) == nil {
+			removed++
+		}
+	})
+	return removed, err
+}
+
+func (c *Cache) scan(fn func(p string, valid, expired bool)) error {
+	if c.err != nil {
+		return c.err
+	}
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, p := range files {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		var entry Entry
+		valid := json.Unmarshal(data, &entry) == nil && !entry.CachedAt.IsZero()
+		fn(p, valid, valid && time.Since(entry.CachedAt) > c.ttl)
+	}
+	return nil
 }
 
-// Key generates a cache key from zip code and days.
-func Key(zip string, days int) string {
-	today := time.Now().Format("2006-01-02")
-	h := sha256.Sum256([]byte(fmt.Sprintf("%s_%d_%s", zip, days, today)))
+// Key generates a cache key from zip code, days, and the language the
+// API answered in. Dates are not part of the key: the machine's date may
+// not be the location's, so callers check that a cached forecast is still
+// current themselves.
+func Key(zip string, days int, lang string) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s_%d_%s", zip, days, lang)))
 	return fmt.Sprintf("%x", h[:8])
 }
 
@@ -1,7 +1,14 @@
 package cache
 
 import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSetAndGet(t *testing.T) {
@@ -36,14 +43,84 @@ func TestGetMissing(t *testing.T) {
 }
 
 func TestCacheKey(t *testing.T) {
-	k1 := Key("94025", 5)
-	k2 := Key("94025", 5)
-	k3 := Key("10001", 5)
+	k1 := Key("94025", 5, "en")
+	k2 := Key("94025", 5, "en")
+	k3 := Key("10001", 5, "en")
+	k4 := Key("94025", 5, "ja")
 
 	if k1 != k2 {
 		t.Error("same inputs should produce same key")
 	}
-	if k1 == k3 {
+	if k1 == k3 || k1 == k4 {
 		t.Error("different inputs should produce different keys")
 	}
 }
+
+func TestNoCacheDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("HOME", "")
+
+	c := New("")
+	if _, err := c.Dir(); err == nil {
+		t.Fatal("expected an error when no cache directory can be resolved")
+	}
+	if err := c.Set("k", "v"); err == nil {
+		t.Error("Set should fail without a cache directory")
+	}
+	if _, _, ok := c.Get("k"); ok {
+		t.Error("Get should miss without a cache directory")
+	}
+}
+
+func TestInspectAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	c := New(dir)
+
+	if err := c.Set("fresh", "ok"); err != nil {
+		t.Fatal(err)
+	}
+	stale, _ := json.Marshal(Entry{Data: json.RawMessage(`"old"`), CachedAt: time.Now().Add(-2 * defaultTTL)})
+	os.WriteFile(filepath.Join(dir, "stale.json"), stale, 0o600)
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{not json"), 0o600)
+
+	st, err := c.Inspect()
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if st != (Stats{Entries: 1, Expired: 1, Corrupt: 1}) {
+		t.Errorf("got %+v", st)
+	}
+
+	n, err := c.Prune()
+	if err != nil || n != 2 {
+		t.Errorf("Prune: got %d, %v; want 2", n, err)
+	}
+	if _, _, ok := c.Get("fresh"); !ok {
+		t.Error("Prune removed a fresh entry")
+	}
+
+	if st, err := New(filepath.Join(dir, "missing")).Inspect(); err != nil || st != (Stats{}) {
+		t.Errorf("missing dir: got %+v, %v", st, err)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := New(t.TempDir()).WithLogger(log)
+
+	c.Get("k1")
+	c.Set("k1", "data")
+	c.Get("k1")
+
+	out := buf.String()
+	for _, want := range []string{
+		`msg="cache miss" key=k1 reason=absent`,
+		`msg="cache store" key=k1`,
+		`msg="cache hit" key=k1 age=`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log missing %q:\n%s", want, out)
+		}
+	}
+}
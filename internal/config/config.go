@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/shunito/pollenow/internal/apikey"
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/xdg"
 )
 
 const (
 	DefaultDays = 5
-	appDir      = "pollenow"
 	configFile  = "config.yaml"
 )
 
@@ -19,52 +23,188 @@ var (
 	ErrNoAPIKey = errors.New("no API key configured")
 )
 
-// Path is a function that returns the config file path.
+// Path is a function that returns the config file path,
+// $XDG_CONFIG_HOME/pollenow/config.yaml by default.
 // It is a variable so tests can override it.
-var Path = func() string {
-	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".config", appDir, configFile)
+var Path = func() (string, error) {
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configFile), nil
 }
 
+// DefaultLeadTime is how long before exposure a medication is taken when
+// its lead_time is not set.
+const DefaultLeadTime = 12 * time.Hour
+
 // Config represents the application configuration stored on disk.
+//
+// Each field declares its schema once in struct tags: desc is the help
+// text, validate names a validator from the validators table, and secret
+// marks values that are redacted in output. See Schema.
 type Config struct {
-	APIKey     string `yaml:"api_key"`
-	DefaultZIP string `yaml:"default_zip,omitempty"`
-	Days       int    `yaml:"days,omitempty"`
+	APIKey      string             `yaml:"api_key,omitempty" validate:"apikey" secret:"true" desc:"Google API key with the Pollen and Geocoding APIs enabled"`
+	APIKeyFile  string             `yaml:"api_key_file,omitempty" desc:"File holding the API key, read only when a request is made"`
+	APIKeyCmd   string             `yaml:"api_key_command,omitempty" desc:"Command whose output is the API key, run only when a request is made"`
+	DefaultZIP  string             `yaml:"default_zip,omitempty" validate:"zip" desc:"ZIP code used when none is given"`
+	Days        int                `yaml:"days,omitempty" validate:"days" desc:"Number of forecast days (1-5)"`
+	Language    string             `yaml:"language,omitempty" validate:"language" desc:"Language for API content and messages, e.g. ja or pt-BR (default: en)"`
+	TimeZone    string             `yaml:"time_zone,omitempty" validate:"timezone" desc:"IANA time zone for day labels, e.g. America/Chicago (default: the location's own)"`
+	Thresholds  *pollen.Thresholds `yaml:"thresholds,omitempty" desc:"UPI values treated as high and low"`
+	Output      Output             `yaml:"output,omitempty" desc:"Display preferences"`
+	Formats     map[string]string  `yaml:"formats,omitempty" validate:"formats" desc:"Named Go templates for --format, as a YAML map"`
+	Medications []Medication       `yaml:"medications,omitempty" validate:"medications" desc:"Medications for pollenow plan, as a YAML list"`
+	Usage       Usage              `yaml:"usage,omitempty" desc:"Daily limits on Google API requests"`
+	HTTP        HTTP               `yaml:"http,omitempty" desc:"Network settings shared by both APIs"`
+	Geocoding   Endpoint           `yaml:"geocoding,omitempty" desc:"Geocoding API endpoint settings"`
+	Pollen      Endpoint           `yaml:"pollen,omitempty" desc:"Pollen API endpoint settings"`
+	Tracing     Tracing            `yaml:"tracing,omitempty" desc:"OpenTelemetry tracing"`
+
+	// Profile names the profile to apply when neither --profile nor
+	// POLLENOW_PROFILE is set. Profiles holds named overlays: any key
+	// above can be set inside a profile.
+	Profile  string               `yaml:"profile,omitempty" desc:"Profile applied when --profile and POLLENOW_PROFILE are not set"`
+	Profiles map[string]yaml.Node `yaml:"profiles,omitempty" schema:"-"`
 }
 
-// Load reads config from ~/.config/pollenow/config.yaml.
-// Returns a zero-value Config (not an error) if the file does not exist.
-func Load() (*Config, error) {
-	p := Path()
-	data, err := os.ReadFile(p)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return &Config{Days: DefaultDays}, nil
-		}
-		return nil, fmt.Errorf("reading config: %w", err)
+// Output holds display preferences.
+type Output struct {
+	Compact   bool   `yaml:"compact,omitempty" desc:"Use one-line output by default"`
+	Theme     string `yaml:"theme,omitempty" validate:"theme" desc:"Color theme: default, light, high-contrast, deuteranopia, or monochrome"`
+	Color     string `yaml:"color,omitempty" validate:"color" desc:"When to use color: auto (default; off when NO_COLOR is set), always, or never"`
+	ASCII     bool   `yaml:"ascii,omitempty" desc:"Draw with ASCII instead of emoji and box-drawing characters"`
+	APIColors bool   `yaml:"api_colors,omitempty" desc:"Color levels with the Pollen API's own colors"`
+}
+
+// Usage caps the number of Google API requests per day. Zero means no cap.
+type Usage struct {
+	SoftDailyCap int `yaml:"soft_daily_cap,omitempty" validate:"count" desc:"Warn after this many API requests in a day"`
+	HardDailyCap int `yaml:"hard_daily_cap,omitempty" validate:"count" desc:"Refuse API requests after this many in a day"`
+}
+
+// HTTP holds network settings shared by both APIs.
+type HTTP struct {
+	Proxy     string `yaml:"proxy,omitempty" validate:"url" desc:"Proxy URL (default: HTTPS_PROXY from the environment)"`
+	CABundle  string `yaml:"ca_bundle,omitempty" desc:"PEM file of extra CA certificates to trust"`
+	UserAgent string `yaml:"user_agent,omitempty" desc:"User-Agent header sent with API requests"`
+	Timeout   string `yaml:"timeout,omitempty" validate:"duration" desc:"Per-request timeout, e.g. 10s"`
+}
+
+// Tracing selects where OpenTelemetry spans are exported. With no
+// exporter, tracing is off.
+type Tracing struct {
+	Exporter string `yaml:"exporter,omitempty" validate:"exporter" desc:"Span exporter: none, otlp, or stdout"`
+	Endpoint string `yaml:"endpoint,omitempty" validate:"url" desc:"OTLP/HTTP collector URL (default: OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318)"`
+}
+
+// Endpoint configures how one API is reached. Empty fields fall back to
+// the http section, then to built-in defaults.
+type Endpoint struct {
+	BaseURL   string `yaml:"base_url,omitempty" validate:"url" desc:"API base URL, e.g. an internal gateway or mock server"`
+	Proxy     string `yaml:"proxy,omitempty" validate:"url" desc:"Proxy URL for this API only"`
+	CABundle  string `yaml:"ca_bundle,omitempty" desc:"PEM file of extra CA certificates for this API only"`
+	UserAgent string `yaml:"user_agent,omitempty" desc:"User-Agent header for this API only"`
+	Timeout   string `yaml:"timeout,omitempty" validate:"duration" desc:"Per-request timeout for this API only"`
+}
+
+// DefaultTimeout bounds each API request when no timeout is configured.
+const DefaultTimeout = 10 * time.Second
+
+// ResolvedEndpoint is an Endpoint merged with the http section.
+type ResolvedEndpoint struct {
+	BaseURL   string
+	Proxy     string
+	CABundle  string
+	UserAgent string
+	Timeout   time.Duration
+}
+
+// Endpoint returns the settings for the named API ("geocoding" or "pollen"),
+// with unset fields filled from the http section and defaults.
+func (c *Config) Endpoint(api string) ResolvedEndpoint {
+	e := c.Geocoding
+	if api == "pollen" {
+		e = c.Pollen
+	}
+	r := ResolvedEndpoint{
+		BaseURL:   e.BaseURL,
+		Proxy:     firstNonEmpty(e.Proxy, c.HTTP.Proxy),
+		CABundle:  firstNonEmpty(e.CABundle, c.HTTP.CABundle),
+		UserAgent: firstNonEmpty(e.UserAgent, c.HTTP.UserAgent),
+		Timeout:   DefaultTimeout,
 	}
+	// Timeouts are checked by Check; an unparsable one keeps the default.
+	if d, err := time.ParseDuration(firstNonEmpty(e.Timeout, c.HTTP.Timeout)); err == nil && d > 0 {
+		r.Timeout = d
+	}
+	return r
+}
 
-	cfg := &Config{Days: DefaultDays}
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("parsing config: %w", err)
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
 	}
+	return ""
+}
+
+// Medication describes a pre-emptive medication and the pollen it targets.
+type Medication struct {
+	Name     string   `yaml:"name"`
+	Targets  []string `yaml:"targets"`             // pollen types ("tree") or plants ("birch")
+	LeadTime string   `yaml:"lead_time,omitempty"` // Go duration, e.g. "12h"
+}
 
-	// Environment variable override
-	if envKey := os.Getenv("POLLENOW_API_KEY"); envKey != "" {
-		cfg.APIKey = envKey
+// Lead returns the parsed lead time, or DefaultLeadTime if unset.
+func (m Medication) Lead() (time.Duration, error) {
+	if m.LeadTime == "" {
+		return DefaultLeadTime, nil
+	}
+	d, err := time.ParseDuration(m.LeadTime)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("medication %q: invalid lead_time %q (use e.g. 12h or 30m)", m.Name, m.LeadTime)
 	}
+	return d, nil
+}
 
-	if cfg.Days < 1 || cfg.Days > 5 {
-		cfg.Days = DefaultDays
+// Validate checks that the medication has a name, targets, and a valid lead time.
+func (m Medication) Validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("medication is missing a name")
+	}
+	if len(m.Targets) == 0 {
+		return fmt.Errorf("medication %q has no targets", m.Name)
 	}
+	_, err := m.Lead()
+	return err
+}
 
+// Load resolves the effective config from every layer.
+// See LoadWithOrigins for the resolution order.
+func Load() (*Config, error) {
+	cfg, _, err := LoadWithOrigins()
+	return cfg, err
+}
+
+// LoadFile reads a single config file without applying any other layer.
+// Returns a zero-value Config (not an error) if the file does not exist.
+func LoadFile(p string) (*Config, error) {
+	cfg := &Config{}
+	if _, err := applyFile(cfg, p, false); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
-// Save writes config to ~/.config/pollenow/config.yaml.
+// Save writes config to the writable config file: the --config file if one
+// was given, otherwise the user config file.
 func Save(cfg *Config) error {
-	p := Path()
+	p, err := WritePath()
+	if err != nil {
+		return err
+	}
 	dir := filepath.Dir(p)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("creating config dir: %w", err)
@@ -82,16 +222,53 @@ func Save(cfg *Config) error {
 	return nil
 }
 
+// PollenThresholds returns the configured thresholds, or the defaults.
+func (c *Config) PollenThresholds() pollen.Thresholds {
+	if c.Thresholds == nil {
+		return pollen.DefaultThresholds
+	}
+	return *c.Thresholds
+}
+
+// KeySource returns where the API key comes from. An inline api_key wins
+// over api_key_file, which wins over api_key_command, so POLLENOW_API_KEY
+// can always override a configured reference. Nothing is read or run until
+// the source's Key method is called.
+func (c *Config) KeySource() (apikey.Source, error) {
+	switch {
+	case c.APIKey != "":
+		return apikey.Static(c.APIKey), nil
+	case c.APIKeyFile != "":
+		return apikey.File(c.APIKeyFile), nil
+	case c.APIKeyCmd != "":
+		return apikey.Command(c.APIKeyCmd), nil
+	default:
+		return nil, ErrNoAPIKey
+	}
+}
+
 // Validate checks that the config has required fields.
 func (c *Config) Validate() error {
-	if c.APIKey == "" {
-		return ErrNoAPIKey
+	if _, err := c.KeySource(); err != nil {
+		return err
 	}
-	return nil
+	return c.checkThresholds()
+}
+
+// checkThresholds validates the thresholds section, if set.
+func (c *Config) checkThresholds() error {
+	if c.Thresholds == nil {
+		return nil
+	}
+	return c.Thresholds.Validate()
 }
 
 // Exists returns true if the config file exists on disk.
 func Exists() bool {
-	_, err := os.Stat(Path())
+	p, err := Path()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(p)
 	return err == nil
 }
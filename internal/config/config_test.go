@@ -1,16 +1,20 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/shunito/pollenow/internal/apikey"
 )
 
 func TestSaveAndLoad(t *testing.T) {
 	// Use temp dir to avoid touching real config
 	tmpDir := t.TempDir()
 	origPath := Path
-	Path = func() string { return filepath.Join(tmpDir, "config.yaml") }
+	Path = func() (string, error) { return filepath.Join(tmpDir, "config.yaml"), nil }
 	defer func() { Path = origPath }()
 
 	cfg := &Config{
@@ -42,7 +46,7 @@ func TestSaveAndLoad(t *testing.T) {
 func TestLoadMissingFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	origPath := Path
-	Path = func() string { return filepath.Join(tmpDir, "nonexistent", "config.yaml") }
+	Path = func() (string, error) { return filepath.Join(tmpDir, "nonexistent", "config.yaml"), nil }
 	defer func() { Path = origPath }()
 
 	cfg, err := Load()
@@ -66,10 +70,30 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestKeySource(t *testing.T) {
+	cfg := &Config{APIKeyCmd: "pass show google/pollen"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("api_key_command alone should be valid, got %v", err)
+	}
+	if src, _ := cfg.KeySource(); src != apikey.Command("pass show google/pollen") {
+		t.Errorf("got %#v, want command source", src)
+	}
+
+	cfg.APIKeyFile = "/run/secrets/pollen_key"
+	if src, _ := cfg.KeySource(); src != apikey.File("/run/secrets/pollen_key") {
+		t.Errorf("api_key_file should win over api_key_command, got %#v", src)
+	}
+
+	cfg.APIKey = "inline"
+	if src, _ := cfg.KeySource(); src != apikey.Static("inline") {
+		t.Errorf("api_key should win over references, got %#v", src)
+	}
+}
+
 func TestEnvOverride(t *testing.T) {
 	tmpDir := t.TempDir()
 	origPath := Path
-	Path = func() string { return filepath.Join(tmpDir, "config.yaml") }
+	Path = func() (string, error) { return filepath.Join(tmpDir, "config.yaml"), nil }
 	defer func() { Path = origPath }()
 
 	cfg := &Config{APIKey: "file-key", Days: DefaultDays}
@@ -92,7 +116,7 @@ func TestEnvOverride(t *testing.T) {
 func TestInvalidDaysReset(t *testing.T) {
 	tmpDir := t.TempDir()
 	origPath := Path
-	Path = func() string { return filepath.Join(tmpDir, "config.yaml") }
+	Path = func() (string, error) { return filepath.Join(tmpDir, "config.yaml"), nil }
 	defer func() { Path = origPath }()
 
 	cfg := &Config{APIKey: "key", Days: 99}
@@ -108,3 +132,72 @@ func TestInvalidDaysReset(t *testing.T) {
 		t.Errorf("Days: got %d, want default %d", loaded.Days, DefaultDays)
 	}
 }
+
+func TestMedicationsRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	origPath := Path
+	Path = func() (string, error) { return filepath.Join(tmpDir, "config.yaml"), nil }
+	defer func() { Path = origPath }()
+
+	cfg := &Config{
+		APIKey: "key",
+		Medications: []Medication{
+			{Name: "cetirizine", Targets: []string{"tree", "grass"}, LeadTime: "10h"},
+			{Name: "fluticasone", Targets: []string{"birch"}},
+		},
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Medications) != 2 {
+		t.Fatalf("Medications: got %d, want 2", len(loaded.Medications))
+	}
+
+	lead, err := loaded.Medications[0].Lead()
+	if err != nil || lead != 10*time.Hour {
+		t.Errorf("Lead: got %v, %v; want 10h", lead, err)
+	}
+	lead, err = loaded.Medications[1].Lead()
+	if err != nil || lead != DefaultLeadTime {
+		t.Errorf("default Lead: got %v, %v; want %v", lead, err, DefaultLeadTime)
+	}
+}
+
+func TestMedicationValidate(t *testing.T) {
+	tests := []Medication{
+		{Targets: []string{"tree"}},
+		{Name: "cetirizine"},
+		{Name: "cetirizine", Targets: []string{"tree"}, LeadTime: "overnight"},
+	}
+	for _, m := range tests {
+		if err := m.Validate(); err == nil {
+			t.Errorf("%+v: expected validation error", m)
+		}
+	}
+
+	ok := Medication{Name: "cetirizine", Targets: []string{"tree"}, LeadTime: "8h"}
+	if err := ok.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadNoConfigDir(t *testing.T) {
+	origPath := Path
+	Path = func() (string, error) { return "", errors.New("cannot resolve directory") }
+	defer func() { Path = origPath }()
+
+	if _, err := Load(); err == nil {
+		t.Error("Load should fail when the config path cannot be resolved")
+	}
+	if err := Save(&Config{APIKey: "key"}); err == nil {
+		t.Error("Save should fail when the config path cannot be resolved")
+	}
+	if Exists() {
+		t.Error("Exists should be false when the config path cannot be resolved")
+	}
+}
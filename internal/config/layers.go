@@ -0,0 +1,344 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const envPrefix = "POLLENOW_"
+
+// SystemPath returns the organization-wide config file path.
+// It is a variable so tests can override it.
+var SystemPath = func() string {
+	return "/etc/pollenow/config.yaml"
+}
+
+// OverridePath is set from the --config flag. The file it names is the
+// highest-priority layer, and Save writes to it instead of the user file.
+var OverridePath string
+
+// ProfileOverride is set from the --profile flag and takes precedence over
+// POLLENOW_PROFILE and the profile key in config files.
+var ProfileOverride string
+
+var (
+	ErrUnknownProfile = errors.New("unknown profile")
+)
+
+// Origins maps each config key to a description of where its effective
+// value came from, e.g. "user file ~/.config/pollenow/config.yaml". Files
+// and profiles are recorded per top-level key; nested keys set through the
+// environment, such as "pollen.timeout", are recorded under the full key.
+type Origins map[string]string
+
+// LoadWithOrigins resolves the effective config and reports where each key
+// came from. Layers are applied in order, later ones winning:
+//
+//  1. built-in defaults
+//  2. the system file (/etc/pollenow/config.yaml)
+//  3. the user file ($XDG_CONFIG_HOME/pollenow/config.yaml)
+//  4. the selected profile, if any
+//  5. POLLENOW_<KEY> environment variables, e.g. POLLENOW_DEFAULT_ZIP
+//  6. the file given with --config
+//
+// Profiles may be defined in any of the files. The profile is chosen by
+// --profile, then POLLENOW_PROFILE, then the profile key.
+//
+// The key sources (api_key, api_key_file, api_key_command) are resolved by
+// layer: a layer that sets any of them replaces those set below it, so an
+// api_key_command in the --config file is not shadowed by an api_key in
+// the user file.
+//
+// Missing system and user files are skipped; a missing --config file is an error.
+func LoadWithOrigins() (*Config, Origins, error) {
+	cfg := &Config{Days: DefaultDays}
+	origins := Origins{"days": "default"}
+
+	if err := keySourceLayer(cfg, origins, func() error {
+		return applyLayerFile(cfg, origins, SystemPath(), "system file", false)
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	userPath, err := Path()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := keySourceLayer(cfg, origins, func() error {
+		return applyLayerFile(cfg, origins, userPath, "user file", false)
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	// The --config file is applied last, but its profiles and profile
+	// selection must be known before the profile layer.
+	overlay := &Config{}
+	if OverridePath != "" {
+		if _, err := applyFile(overlay, OverridePath, true); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := keySourceLayer(cfg, origins, func() error {
+		return applyProfile(cfg, overlay, origins)
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if err := keySourceLayer(cfg, origins, func() error {
+		return applyEnv(cfg, origins)
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if OverridePath != "" {
+		if err := keySourceLayer(cfg, origins, func() error {
+			return applyLayerFile(cfg, origins, OverridePath, "--config file", true)
+		}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if cfg.Days < 1 || cfg.Days > 5 {
+		cfg.Days = DefaultDays
+		origins["days"] = "default (configured value out of range)"
+	}
+
+	return cfg, origins, nil
+}
+
+// keySources returns the fields that can supply the API key, by key.
+func (c *Config) keySources() map[string]*string {
+	return map[string]*string{
+		"api_key":         &c.APIKey,
+		"api_key_file":    &c.APIKeyFile,
+		"api_key_command": &c.APIKeyCmd,
+	}
+}
+
+// keySourceLayer runs apply, which merges one layer into cfg. If the layer
+// set a key source, those left over from lower layers are cleared, so the
+// highest layer naming a key source decides where the key comes from.
+func keySourceLayer(cfg *Config, origins Origins, apply func() error) error {
+	before := make(map[string]string)
+	for k := range cfg.keySources() {
+		before[k] = origins[k]
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+
+	sources := cfg.keySources()
+	set := false
+	for k, field := range sources {
+		if origins[k] != before[k] && *field != "" {
+			set = true
+		}
+	}
+	if !set {
+		return nil
+	}
+	for k, field := range sources {
+		if origins[k] == before[k] {
+			*field = ""
+			delete(origins, k)
+		}
+	}
+	return nil
+}
+
+// applyProfile selects a profile and overlays its keys onto cfg.
+func applyProfile(cfg, overlay *Config, origins Origins) error {
+	name, source := ProfileOverride, "--profile"
+	if name == "" {
+		name, source = os.Getenv(EnvVar("profile")), "env "+EnvVar("profile")
+	}
+	if name == "" {
+		name, source = overlay.Profile, "--config file "+OverridePath
+	}
+	if name == "" {
+		name, source = cfg.Profile, origins["profile"]
+	}
+	if name == "" {
+		return nil
+	}
+
+	profiles := make(map[string]yaml.Node, len(cfg.Profiles)+len(overlay.Profiles))
+	for k, v := range cfg.Profiles {
+		profiles[k] = v
+	}
+	for k, v := range overlay.Profiles {
+		profiles[k] = v
+	}
+
+	node, ok := profiles[name]
+	if !ok {
+		return fmt.Errorf("%w %q (%s) — defined profiles: %s", ErrUnknownProfile, name, source, profileList(profiles))
+	}
+	if err := node.Decode(cfg); err != nil {
+		return fmt.Errorf("parsing profile %q: %w", name, err)
+	}
+
+	cfg.Profile = name
+	origins["profile"] = source
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		origins[node.Content[i].Value] = "profile " + name
+	}
+	return nil
+}
+
+// ProfileNames returns the names of the profiles defined in cfg, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for k := range c.Profiles {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func profileList(profiles map[string]yaml.Node) string {
+	if len(profiles) == 0 {
+		return "(none)"
+	}
+	c := &Config{Profiles: profiles}
+	return strings.Join(c.ProfileNames(), ", ")
+}
+
+// WritePath returns the file Save writes to: the --config file if set,
+// otherwise the user config file.
+func WritePath() (string, error) {
+	if OverridePath != "" {
+		return OverridePath, nil
+	}
+	return Path()
+}
+
+// Keys returns every top-level config key in declaration order.
+func Keys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if k := yamlKey(t.Field(i)); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// EnvVar returns the environment variable that overrides key. Nested keys
+// use underscores: pollen.base_url is POLLENOW_POLLEN_BASE_URL.
+func EnvVar(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// applyLayerFile merges one file into cfg and records the keys it set.
+func applyLayerFile(cfg *Config, origins Origins, p, label string, required bool) error {
+	keys, err := applyFile(cfg, p, required)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		origins[k] = label + " " + p
+	}
+	return nil
+}
+
+// applyFile decodes the file at p over cfg and returns the top-level keys
+// it contained. A missing file yields nil keys unless required is set.
+func applyFile(cfg *Config, p string, required bool) ([]string, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading config %s: %w", p, err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", p, err)
+	}
+
+	var present map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &present); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", p, err)
+	}
+	keys := make([]string, 0, len(present))
+	for k := range present {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// applyEnv overrides each key whose POLLENOW_<KEY> variable is set.
+// Scalar keys take the raw value; list and section keys take YAML flow
+// syntax, e.g. POLLENOW_MEDICATIONS='[{name: cetirizine, targets: [tree]}]'.
+func applyEnv(cfg *Config, origins Origins) error {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		key := yamlKey(t.Field(i))
+		if key == "" || key == "profile" || key == "profiles" {
+			// POLLENOW_PROFILE is handled by applyProfile.
+			continue
+		}
+		name := EnvVar(key)
+		raw, ok := os.LookupEnv(name)
+		if !ok || raw == "" {
+			continue
+		}
+
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(raw)
+		case reflect.Int:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a number", name, raw)
+			}
+			field.SetInt(int64(n))
+		default:
+			if err := yaml.Unmarshal([]byte(raw), field.Addr().Interface()); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		origins[key] = "env " + name
+	}
+
+	// Nested keys can also be set one at a time, applied after the
+	// whole-section variables above.
+	for _, f := range Schema {
+		if !strings.Contains(f.Key, ".") {
+			continue
+		}
+		name := EnvVar(f.Key)
+		raw, ok := os.LookupEnv(name)
+		if !ok || raw == "" {
+			continue
+		}
+		if err := f.Set(cfg, raw); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		origins[f.Key] = "env " + name
+	}
+	return nil
+}
+
+func yamlKey(f reflect.StructField) string {
+	tag := f.Tag.Get("yaml")
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,304 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shunito/pollenow/internal/apikey"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+// useTempLayers points the system and user files into a temp dir and
+// clears any POLLENOW_* variables from the environment.
+func useTempLayers(t *testing.T) (systemPath, userPath string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	systemPath = filepath.Join(tmpDir, "etc", "config.yaml")
+	userPath = filepath.Join(tmpDir, "user", "config.yaml")
+
+	origPath, origSystem, origOverride := Path, SystemPath, OverridePath
+	Path = func() (string, error) { return userPath, nil }
+	SystemPath = func() string { return systemPath }
+	OverridePath = ""
+	t.Cleanup(func() { Path, SystemPath, OverridePath = origPath, origSystem, origOverride })
+
+	for _, k := range Keys() {
+		t.Setenv(EnvVar(k), "")
+	}
+	for _, f := range Schema {
+		t.Setenv(EnvVar(f.Key), "")
+	}
+	return systemPath, userPath
+}
+
+func writeFile(t *testing.T, p, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLayerOrder(t *testing.T) {
+	systemPath, userPath := useTempLayers(t)
+	writeFile(t, systemPath, "api_key: org-key\ndefault_zip: \"10001\"\ndays: 3\n")
+	writeFile(t, userPath, "default_zip: \"94025\"\n")
+	t.Setenv("POLLENOW_DAYS", "2")
+
+	cfg, origins, err := LoadWithOrigins()
+	if err != nil {
+		t.Fatalf("LoadWithOrigins failed: %v", err)
+	}
+
+	if cfg.APIKey != "org-key" || cfg.DefaultZIP != "94025" || cfg.Days != 2 {
+		t.Errorf("got %+v", cfg)
+	}
+	if !strings.HasPrefix(origins["api_key"], "system file") {
+		t.Errorf("api_key origin: got %q", origins["api_key"])
+	}
+	if !strings.HasPrefix(origins["default_zip"], "user file") {
+		t.Errorf("default_zip origin: got %q", origins["default_zip"])
+	}
+	if origins["days"] != "env POLLENOW_DAYS" {
+		t.Errorf("days origin: got %q", origins["days"])
+	}
+}
+
+func TestKeySourceByLayer(t *testing.T) {
+	systemPath, userPath := useTempLayers(t)
+	writeFile(t, systemPath, "api_key: org-key\n")
+	writeFile(t, userPath, "api_key_command: pass show google/pollen\n")
+
+	cfg, origins, err := LoadWithOrigins()
+	if err != nil {
+		t.Fatalf("LoadWithOrigins failed: %v", err)
+	}
+	if src, _ := cfg.KeySource(); src != apikey.Command("pass show google/pollen") {
+		t.Errorf("user file key source should replace the system key, got %#v", src)
+	}
+	if cfg.APIKey != "" || origins["api_key"] != "" {
+		t.Errorf("system api_key left behind: %q from %q", cfg.APIKey, origins["api_key"])
+	}
+
+	// A higher layer with no key source keeps the one below it.
+	override := filepath.Join(t.TempDir(), "override.yaml")
+	writeFile(t, override, "default_zip: \"10001\"\n")
+	OverridePath = override
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if src, _ := cfg.KeySource(); src != apikey.Command("pass show google/pollen") {
+		t.Errorf("key source lost to a layer without one, got %#v", src)
+	}
+
+	// POLLENOW_API_KEY still overrides everything below it.
+	t.Setenv("POLLENOW_API_KEY", "env-key")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if src, _ := cfg.KeySource(); src != apikey.Static("env-key") || cfg.APIKeyCmd != "" {
+		t.Errorf("got source %#v, command %q", src, cfg.APIKeyCmd)
+	}
+}
+
+func TestEnvAppliesWithoutFile(t *testing.T) {
+	useTempLayers(t)
+	t.Setenv("POLLENOW_API_KEY", "env-key")
+	t.Setenv("POLLENOW_DEFAULT_ZIP", "02139")
+	t.Setenv("POLLENOW_MEDICATIONS", "[{name: cetirizine, targets: [tree]}]")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.APIKey != "env-key" || cfg.DefaultZIP != "02139" {
+		t.Errorf("env override ignored without a config file: %+v", cfg)
+	}
+	if len(cfg.Medications) != 1 || cfg.Medications[0].Targets[0] != "tree" {
+		t.Errorf("Medications: got %+v", cfg.Medications)
+	}
+}
+
+func TestNestedEnv(t *testing.T) {
+	_, userPath := useTempLayers(t)
+	writeFile(t, userPath, "http:\n  timeout: 20s\npollen:\n  base_url: https://gateway.example.com/pollen\n")
+	t.Setenv("POLLENOW_HTTP_PROXY", "http://proxy.internal:3128")
+	t.Setenv("POLLENOW_POLLEN_TIMEOUT", "3s")
+
+	cfg, origins, err := LoadWithOrigins()
+	if err != nil {
+		t.Fatalf("LoadWithOrigins failed: %v", err)
+	}
+
+	p := cfg.Endpoint("pollen")
+	if p.BaseURL != "https://gateway.example.com/pollen" || p.Proxy != "http://proxy.internal:3128" || p.Timeout != 3*time.Second {
+		t.Errorf("pollen endpoint: got %+v", p)
+	}
+	if g := cfg.Endpoint("geocoding"); g.BaseURL != "" || g.Timeout != 20*time.Second {
+		t.Errorf("geocoding should fall back to the http section: got %+v", g)
+	}
+	if origins["pollen.timeout"] != "env POLLENOW_POLLEN_TIMEOUT" {
+		t.Errorf("pollen.timeout origin: got %q", origins["pollen.timeout"])
+	}
+	if !strings.HasPrefix(origins["pollen"], "user file") {
+		t.Errorf("pollen origin: got %q", origins["pollen"])
+	}
+
+	t.Setenv("POLLENOW_GEOCODING_BASE_URL", "not a url")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "POLLENOW_GEOCODING_BASE_URL") {
+		t.Errorf("expected error naming POLLENOW_GEOCODING_BASE_URL, got %v", err)
+	}
+}
+
+func TestEnvInvalidNumber(t *testing.T) {
+	useTempLayers(t)
+	t.Setenv("POLLENOW_DAYS", "three")
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "POLLENOW_DAYS") {
+		t.Errorf("expected error naming POLLENOW_DAYS, got %v", err)
+	}
+}
+
+func TestOverridePath(t *testing.T) {
+	_, userPath := useTempLayers(t)
+	writeFile(t, userPath, "api_key: user-key\n")
+	t.Setenv("POLLENOW_API_KEY", "env-key")
+
+	override := filepath.Join(t.TempDir(), "ci.yaml")
+	writeFile(t, override, "api_key: flag-key\n")
+	OverridePath = override
+
+	cfg, origins, err := LoadWithOrigins()
+	if err != nil {
+		t.Fatalf("LoadWithOrigins failed: %v", err)
+	}
+	if cfg.APIKey != "flag-key" {
+		t.Errorf("APIKey: got %q, want flag-key", cfg.APIKey)
+	}
+	if origins["api_key"] != "--config file "+override {
+		t.Errorf("api_key origin: got %q", origins["api_key"])
+	}
+
+	// Save goes to the --config file, not the user file.
+	if err := Save(&Config{APIKey: "saved"}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	saved, _ := LoadFile(override)
+	if saved.APIKey != "saved" {
+		t.Errorf("Save wrote to the wrong file")
+	}
+
+	OverridePath = filepath.Join(t.TempDir(), "missing.yaml")
+	if _, err := Load(); err == nil {
+		t.Error("a missing --config file should be an error")
+	}
+}
+
+func TestLoadFileIgnoresOtherLayers(t *testing.T) {
+	systemPath, userPath := useTempLayers(t)
+	writeFile(t, systemPath, "api_key: org-key\n")
+	writeFile(t, userPath, "default_zip: \"94025\"\n")
+	t.Setenv("POLLENOW_API_KEY", "env-key")
+
+	cfg, err := LoadFile(userPath)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if cfg.APIKey != "" || cfg.DefaultZIP != "94025" {
+		t.Errorf("LoadFile should only read the given file: %+v", cfg)
+	}
+}
+
+const profilesYAML = `api_key: base-key
+default_zip: "94025"
+profile: personal
+profiles:
+  personal:
+    api_key: personal-key
+  work:
+    api_key: work-key
+    default_zip: "10001"
+    days: 3
+    thresholds: {high: 3, low: 1}
+    output: {compact: true}
+`
+
+func TestProfileFromFile(t *testing.T) {
+	_, userPath := useTempLayers(t)
+	writeFile(t, userPath, profilesYAML)
+
+	cfg, origins, err := LoadWithOrigins()
+	if err != nil {
+		t.Fatalf("LoadWithOrigins failed: %v", err)
+	}
+	if cfg.Profile != "personal" || cfg.APIKey != "personal-key" || cfg.DefaultZIP != "94025" {
+		t.Errorf("got profile %q key %q zip %q", cfg.Profile, cfg.APIKey, cfg.DefaultZIP)
+	}
+	if origins["api_key"] != "profile personal" {
+		t.Errorf("api_key origin: got %q", origins["api_key"])
+	}
+	if th := cfg.PollenThresholds(); th.High != 4 {
+		t.Errorf("default thresholds expected, got %+v", th)
+	}
+}
+
+func TestProfileSelection(t *testing.T) {
+	_, userPath := useTempLayers(t)
+	writeFile(t, userPath, profilesYAML)
+	t.Setenv("POLLENOW_PROFILE", "work")
+
+	cfg, origins, err := LoadWithOrigins()
+	if err != nil {
+		t.Fatalf("LoadWithOrigins failed: %v", err)
+	}
+	if cfg.APIKey != "work-key" || cfg.DefaultZIP != "10001" || cfg.Days != 3 || !cfg.Output.Compact {
+		t.Errorf("work profile not applied: %+v", cfg)
+	}
+	if th := cfg.PollenThresholds(); th.High != 3 || th.Low != 1 {
+		t.Errorf("thresholds: got %+v", th)
+	}
+	if origins["profile"] != "env POLLENOW_PROFILE" {
+		t.Errorf("profile origin: got %q", origins["profile"])
+	}
+
+	// --profile beats the environment, and env vars beat the profile.
+	ProfileOverride = "personal"
+	defer func() { ProfileOverride = "" }()
+	t.Setenv("POLLENOW_DEFAULT_ZIP", "02139")
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Profile != "personal" || cfg.APIKey != "personal-key" || cfg.DefaultZIP != "02139" {
+		t.Errorf("got profile %q key %q zip %q", cfg.Profile, cfg.APIKey, cfg.DefaultZIP)
+	}
+}
+
+func TestUnknownProfile(t *testing.T) {
+	_, userPath := useTempLayers(t)
+	writeFile(t, userPath, profilesYAML)
+	ProfileOverride = "school"
+	defer func() { ProfileOverride = "" }()
+
+	_, err := Load()
+	if !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("expected ErrUnknownProfile, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "personal, work") {
+		t.Errorf("error should list defined profiles: %v", err)
+	}
+}
+
+func TestValidateThresholds(t *testing.T) {
+	cfg := &Config{APIKey: "key", Thresholds: &pollen.Thresholds{High: 2, Low: 3}}
+	if err := cfg.Validate(); !errors.Is(err, pollen.ErrInvalidThresholds) {
+		t.Errorf("expected ErrInvalidThresholds when low >= high, got %v", err)
+	}
+}
@@ -0,0 +1,419 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+var (
+	ErrUnknownKey   = errors.New("unknown config key")
+	ErrInvalidValue = errors.New("invalid config value")
+)
+
+// apiKeyRegex matches the format of Google API keys.
+var apiKeyRegex = regexp.MustCompile(`^AIza[0-9A-Za-z_-]{35}$`)
+
+// Kind is the value type of a schema field.
+type Kind string
+
+const (
+	KindString Kind = "string"
+	KindInt    Kind = "int"
+	KindBool   Kind = "bool"
+	KindYAML   Kind = "yaml" // lists and other structured values
+)
+
+// Field describes one settable config key. Fields are derived from the
+// struct tags on Config, so adding a tagged field is all it takes to make
+// it available to config get/set/unset/list and validation.
+type Field struct {
+	Key         string // "days", "thresholds.high"
+	Kind        Kind
+	Description string
+	Secret      bool
+	validator   string
+	index       []int // reflect field index path from Config
+}
+
+// validators maps validate tag values to checks on parsed values. Checks
+// that need other packages, such as time zones and themes, are added with
+// RegisterValidator so config stays free of them.
+var validators = map[string]func(v any) error{
+	"apikey": func(v any) error {
+		if !apiKeyRegex.MatchString(v.(string)) {
+			return errors.New("must be a Google API key: 39 characters starting with AIza")
+		}
+		return nil
+	},
+	"days": func(v any) error {
+		if n := v.(int); n < 1 || n > 5 {
+			return errors.New("must be between 1 and 5")
+		}
+		return nil
+	},
+	"upi": func(v any) error {
+		if n := v.(int); n < 0 || n > 5 {
+			return errors.New("must be a Universal Pollen Index value between 0 and 5")
+		}
+		return nil
+	},
+	"url": func(v any) error {
+		u, err := url.Parse(v.(string))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+			return errors.New("must be an absolute http, https, or socks5 URL")
+		}
+		return nil
+	},
+	"duration": func(v any) error {
+		if d, err := time.ParseDuration(v.(string)); err != nil || d <= 0 {
+			return errors.New("must be a positive duration, e.g. 10s or 1m")
+		}
+		return nil
+	},
+	"count": func(v any) error {
+		if v.(int) < 0 {
+			return errors.New("must be 0 (no limit) or more")
+		}
+		return nil
+	},
+	"medications": func(v any) error {
+		for _, m := range v.([]Medication) {
+			if err := m.Validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// RegisterValidator sets the check for fields tagged validate:"name". The
+// CLI registers its checks at startup; fields whose validator was never
+// registered accept any value of their type.
+func RegisterValidator(name string, check func(v any) error) {
+	validators[name] = check
+}
+
+// zeroValues seeds pointer fields when one of their keys is first set, so
+// setting thresholds.high keeps the default low threshold.
+var zeroValues = map[reflect.Type]func() any{
+	reflect.TypeOf(pollen.Thresholds{}): func() any {
+		t := pollen.DefaultThresholds
+		return &t
+	},
+}
+
+// Schema lists every settable key, in declaration order.
+var Schema = buildSchema(reflect.TypeOf(Config{}), "", nil)
+
+func buildSchema(t reflect.Type, prefix string, index []int) []Field {
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key := yamlKey(sf)
+		if key == "" || sf.Tag.Get("schema") == "-" {
+			continue
+		}
+		key = prefix + key
+		idx := append(append([]int{}, index...), i)
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			fields = append(fields, buildSchema(ft, key+".", idx)...)
+			continue
+		}
+
+		f := Field{
+			Key:         key,
+			Description: sf.Tag.Get("desc"),
+			Secret:      sf.Tag.Get("secret") == "true",
+			validator:   sf.Tag.Get("validate"),
+			index:       idx,
+		}
+		switch ft.Kind() {
+		case reflect.String:
+			f.Kind = KindString
+		case reflect.Int:
+			f.Kind = KindInt
+		case reflect.Bool:
+			f.Kind = KindBool
+		default:
+			f.Kind = KindYAML
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// Lookup returns the schema field for key.
+func Lookup(key string) (Field, error) {
+	key = strings.ToLower(key)
+	for _, f := range Schema {
+		if f.Key == key {
+			return f, nil
+		}
+	}
+	keys := make([]string, 0, len(Schema))
+	for _, f := range Schema {
+		keys = append(keys, f.Key)
+	}
+	return Field{}, fmt.Errorf("%w %q — valid keys: %s", ErrUnknownKey, key, strings.Join(keys, ", "))
+}
+
+// Parse converts a raw string into the field's type and validates it.
+func (f Field) Parse(raw string) (any, error) {
+	var v any
+	switch f.Kind {
+	case KindString:
+		v = raw
+	case KindInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s: %q is not a number", ErrInvalidValue, f.Key, raw)
+		}
+		v = n
+	case KindBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s: %q is not true or false", ErrInvalidValue, f.Key, raw)
+		}
+		v = b
+	default:
+		ptr := reflect.New(f.fieldType())
+		if err := yaml.Unmarshal([]byte(raw), ptr.Interface()); err != nil {
+			return nil, fmt.Errorf("%w for %s: %v", ErrInvalidValue, f.Key, err)
+		}
+		v = ptr.Elem().Interface()
+	}
+
+	if err := f.check(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// check runs the field's validator on a parsed value.
+func (f Field) check(v any) error {
+	validate, ok := validators[f.validator]
+	if !ok {
+		return nil
+	}
+	if err := validate(v); err != nil {
+		if f.Secret {
+			// Never echo secrets back in errors.
+			return fmt.Errorf("%w for %s: %v", ErrInvalidValue, f.Key, err)
+		}
+		return fmt.Errorf("%w for %s (%s): %v", ErrInvalidValue, f.Key, f.format(v), err)
+	}
+	return nil
+}
+
+// Set parses, validates, and stores raw in cfg.
+func (f Field) Set(cfg *Config, raw string) error {
+	v, err := f.Parse(raw)
+	if err != nil {
+		return err
+	}
+	f.value(cfg, true).Set(reflect.ValueOf(v))
+	return nil
+}
+
+// Unset clears the field in cfg. Fields inside a seeded struct such as
+// thresholds revert to their default, and the struct is dropped once it
+// holds nothing but defaults.
+func (f Field) Unset(cfg *Config) {
+	fv := f.value(cfg, false)
+	if !fv.IsValid() {
+		return
+	}
+	if len(f.index) < 2 {
+		fv.Set(reflect.Zero(fv.Type()))
+		return
+	}
+
+	parent := reflect.ValueOf(cfg).Elem().FieldByIndex(f.index[:len(f.index)-1])
+	if parent.Kind() != reflect.Pointer {
+		fv.Set(reflect.Zero(fv.Type()))
+		return
+	}
+	seed, ok := zeroValues[parent.Type().Elem()]
+	if !ok {
+		fv.Set(reflect.Zero(fv.Type()))
+		return
+	}
+	def := reflect.ValueOf(seed()).Elem()
+	fv.Set(def.Field(f.index[len(f.index)-1]))
+	if parent.Elem().Interface() == def.Interface() {
+		parent.Set(reflect.Zero(parent.Type()))
+	}
+}
+
+// Get returns the field's value formatted for output, and whether it is set.
+// Secrets are returned as-is; use Display for output meant for people.
+func (f Field) Get(cfg *Config) (string, bool) {
+	fv := f.value(cfg, false)
+	if !fv.IsValid() || fv.IsZero() {
+		return "", false
+	}
+	return f.format(fv.Interface()), true
+}
+
+// Display returns the value for output, with secrets redacted.
+func (f Field) Display(cfg *Config) string {
+	v, ok := f.Get(cfg)
+	if !ok {
+		return "(not set)"
+	}
+	if f.Secret {
+		return Redact(v)
+	}
+	return v
+}
+
+// Validate checks the field's current value in cfg. Unset values pass.
+func (f Field) Validate(cfg *Config) error {
+	fv := f.value(cfg, false)
+	if !fv.IsValid() || fv.IsZero() {
+		return nil
+	}
+	return f.check(fv.Interface())
+}
+
+// Redact hides all but the first 4 and last 3 characters of a secret.
+func Redact(s string) string {
+	if len(s) > 10 {
+		return s[:4] + "..." + s[len(s)-3:]
+	}
+	return "***"
+}
+
+func (f Field) format(v any) string {
+	if f.Kind != KindYAML {
+		return fmt.Sprint(v)
+	}
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func (f Field) fieldType() reflect.Type {
+	return reflect.TypeOf(Config{}).FieldByIndex(f.index).Type
+}
+
+// value walks the field's index path. With alloc set, nil pointers along
+// the way are allocated; otherwise an invalid Value is returned for them.
+func (f Field) value(cfg *Config, alloc bool) reflect.Value {
+	v := reflect.ValueOf(cfg).Elem()
+	for i, idx := range f.index {
+		v = v.Field(idx)
+		if i == len(f.index)-1 {
+			break
+		}
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				seed, ok := zeroValues[v.Type().Elem()]
+				if ok {
+					v.Set(reflect.ValueOf(seed()))
+				} else {
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+			}
+			v = v.Elem()
+		}
+	}
+	return v
+}
+
+// Check validates every set field plus cross-field rules and returns all
+// problems found. Unlike Validate, a missing API key is not reported.
+func (c *Config) Check() []error {
+	var errs []error
+	for _, f := range Schema {
+		if err := f.Validate(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := c.checkThresholds(); err != nil {
+		errs = append(errs, fmt.Errorf("%w: %v", ErrInvalidValue, err))
+	}
+	if u := c.Usage; u.SoftDailyCap > 0 && u.HardDailyCap > 0 && u.SoftDailyCap > u.HardDailyCap {
+		errs = append(errs, fmt.Errorf("%w: usage.soft_daily_cap (%d) must not exceed usage.hard_daily_cap (%d)", ErrInvalidValue, u.SoftDailyCap, u.HardDailyCap))
+	}
+	for _, name := range c.ProfileNames() {
+		node := c.Profiles[name]
+		p := &Config{}
+		if err := node.Decode(p); err != nil {
+			errs = append(errs, fmt.Errorf("profile %q: %w", name, err))
+			continue
+		}
+		for _, err := range p.Check() {
+			errs = append(errs, fmt.Errorf("profile %q: %w", name, err))
+		}
+	}
+	return errs
+}
+
+// SetProfileKey sets key inside the named profile, creating it if needed.
+func (c *Config) SetProfileKey(profile string, f Field, raw string) error {
+	return c.editProfile(profile, func(p *Config) error { return f.Set(p, raw) })
+}
+
+// UnsetProfileKey clears key inside the named profile.
+func (c *Config) UnsetProfileKey(profile string, f Field) error {
+	if _, ok := c.Profiles[profile]; !ok {
+		return fmt.Errorf("%w %q", ErrUnknownProfile, profile)
+	}
+	return c.editProfile(profile, func(p *Config) error { f.Unset(p); return nil })
+}
+
+// ProfileConfig decodes the named profile's overlay on its own.
+func (c *Config) ProfileConfig(profile string) (*Config, error) {
+	p := &Config{}
+	node, ok := c.Profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownProfile, profile)
+	}
+	if err := node.Decode(p); err != nil {
+		return nil, fmt.Errorf("parsing profile %q: %w", profile, err)
+	}
+	return p, nil
+}
+
+func (c *Config) editProfile(profile string, edit func(*Config) error) error {
+	p := &Config{}
+	if node, ok := c.Profiles[profile]; ok {
+		if err := node.Decode(p); err != nil {
+			return fmt.Errorf("parsing profile %q: %w", profile, err)
+		}
+	}
+	if err := edit(p); err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := node.Encode(p); err != nil {
+		return fmt.Errorf("encoding profile %q: %w", profile, err)
+	}
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]yaml.Node)
+	}
+	c.Profiles[profile] = node
+	return nil
+}
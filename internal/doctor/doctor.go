@@ -0,0 +1,239 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/shunito/pollenow/internal/apikey"
+	"github.com/shunito/pollenow/internal/cache"
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/httpx"
+	"github.com/shunito/pollenow/internal/keycheck"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+// Skew limits. Large skew breaks TLS certificate checks; smaller skew
+// already makes cache ages and day labels wrong.
+const (
+	warnSkew = 30 * time.Second
+	maxSkew  = 5 * time.Minute
+)
+
+// Status is the outcome of one check.
+type Status string
+
+const (
+	Pass Status = "pass"
+	Warn Status = "warn"
+	Fail Status = "fail"
+	Skip Status = "skip"
+)
+
+// Check is the result of one diagnostic.
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"` // how to fix a warning or failure
+}
+
+// ConfigFile checks that the config file at p parses, validates, and is
+// not readable by other users.
+func ConfigFile(p string) Check {
+	c := Check{Name: "Config file"}
+	info, err := os.Stat(p)
+	if errors.Is(err, os.ErrNotExist) {
+		c.Status, c.Detail = Warn, fmt.Sprintf("%s does not exist", p)
+		c.Hint = "run: pollenow config init (not needed if POLLENOW_* variables supply the settings)"
+		return c
+	}
+	if err != nil {
+		c.Status, c.Detail = Fail, err.Error()
+		return c
+	}
+
+	cfg, err := config.LoadFile(p)
+	if err != nil {
+		c.Status, c.Detail = Fail, err.Error()
+		c.Hint = "run: pollenow config edit"
+		return c
+	}
+	if problems := cfg.Check(); len(problems) > 0 {
+		c.Status, c.Detail = Fail, errors.Join(problems...).Error()
+		c.Hint = "run: pollenow config validate"
+		return c
+	}
+
+	mode := info.Mode().Perm()
+	c.Status, c.Detail = Pass, fmt.Sprintf("%s (%04o)", p, mode)
+	if runtime.GOOS != "windows" && mode&0o077 != 0 {
+		c.Status = Warn
+		c.Detail = fmt.Sprintf("%s is readable by other users (%04o)", p, mode)
+		c.Hint = fmt.Sprintf("run: chmod 600 %s", p)
+	}
+	return c
+}
+
+// KeySource resolves the API key from src and returns it with the check.
+// The key is empty when the check did not pass.
+func KeySource(ctx context.Context, src apikey.Source, err error) (Check, string) {
+	c := Check{Name: "API key source"}
+	if err != nil {
+		c.Status, c.Detail = Fail, err.Error()
+		c.Hint = "run: pollenow config init, or set api_key_command or api_key_file"
+		return c, ""
+	}
+
+	key, err := src.Key(ctx)
+	if err != nil {
+		c.Status, c.Detail = Fail, fmt.Sprintf("%s: %v", src.Describe(), err)
+		return c, ""
+	}
+	c.Status, c.Detail = Pass, fmt.Sprintf("%s (%s)", src.Describe(), config.Redact(key))
+	return c, key
+}
+
+// APIs checks that both Google endpoints are reachable and accept key,
+// using clients built with it. Without a key both checks are skipped.
+func APIs(ctx context.Context, key string, g geocoding.Geocoder, p pollen.PollenClient) []Check {
+	if key == "" {
+		return []Check{
+			{Name: "Geocoding API", Status: Skip, Detail: "no API key"},
+			{Name: "Pollen API", Status: Skip, Detail: "no API key"},
+		}
+	}
+
+	results := keycheck.Check(ctx, g, p)
+	checks := make([]Check, 0, len(results))
+	for _, r := range results {
+		c := Check{Name: r.API, Status: Pass, Detail: "reachable, key accepted"}
+		if r.Status != keycheck.OK {
+			c.Status, c.Hint = Fail, r.Hint()
+			c.Detail = r.Status.String()
+			if r.Err != nil {
+				// Transport errors can include the request URL, key and all.
+				c.Detail += ": " + strings.ReplaceAll(r.Err.Error(), key, config.Redact(key))
+			}
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// CacheDir checks that the cache directory can be written to.
+func CacheDir(c *cache.Cache) Check {
+	ch := Check{Name: "Cache directory"}
+	dir, err := c.Dir()
+	if err != nil {
+		ch.Status, ch.Detail = Fail, err.Error()
+		return ch
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		ch.Status, ch.Detail = Fail, err.Error()
+		return ch
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		ch.Status, ch.Detail = Fail, fmt.Sprintf("%s is not writable: %v", dir, err)
+		ch.Hint = "fix the directory permissions or set XDG_CACHE_HOME"
+		return ch
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	ch.Status, ch.Detail = Pass, dir
+	return ch
+}
+
+// CacheEntries reports expired and corrupt cache files. Expired entries
+// are normal: each is refetched the next time its location is looked up,
+// so only corrupt ones warrant a warning.
+func CacheEntries(c *cache.Cache) Check {
+	ch := Check{Name: "Cache entries"}
+	st, err := c.Inspect()
+	if err != nil {
+		ch.Status, ch.Detail = Fail, err.Error()
+		return ch
+	}
+
+	ch.Status = Pass
+	ch.Detail = fmt.Sprintf("%d current", st.Entries)
+	if st.Expired > 0 {
+		ch.Detail += fmt.Sprintf(", %d expired (refreshed on next lookup)", st.Expired)
+	}
+	if st.Corrupt > 0 {
+		ch.Status = Warn
+		ch.Detail += fmt.Sprintf(", %d corrupt", st.Corrupt)
+		ch.Hint = "run: pollenow doctor --fix"
+	}
+	return ch
+}
+
+// Clock compares the local clock with the Date header from url.
+func Clock(ctx context.Context, client httpx.Client, url string) Check {
+	c := Check{Name: "Clock"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		c.Status, c.Detail = Fail, err.Error()
+		return c
+	}
+
+	sent := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		c.Status, c.Detail = Skip, fmt.Sprintf("could not reach %s", url)
+		return c
+	}
+	resp.Body.Close()
+	received := time.Now()
+
+	remote, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		c.Status, c.Detail = Skip, "server sent no usable Date header"
+		return c
+	}
+
+	// Compare with the midpoint of the round trip. The Date header only has
+	// one-second precision, so skew below that is noise.
+	local := sent.Add(received.Sub(sent) / 2)
+	skew := local.Sub(remote).Round(time.Second)
+	abs := skew
+	if abs < 0 {
+		abs = -abs
+	}
+
+	c.Status, c.Detail = Pass, fmt.Sprintf("within %s of %s", abs, req.URL.Host)
+	switch {
+	case abs > maxSkew:
+		c.Status = Fail
+	case abs > warnSkew:
+		c.Status = Warn
+	default:
+		return c
+	}
+	direction := "ahead"
+	if skew < 0 {
+		direction = "behind"
+	}
+	c.Detail = fmt.Sprintf("local clock is %s %s", abs, direction)
+	c.Hint = "enable time synchronization (NTP)"
+	return c
+}
+
+// Failed reports whether any check failed.
+func Failed(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
@@ -4,13 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/shunito/pollenow/internal/cache"
 	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/i18n"
+	"github.com/shunito/pollenow/internal/logging"
 	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/timezone"
 )
 
+var tracer = otel.Tracer("github.com/shunito/pollenow/internal/forecast")
+
 // Result includes the location info, formatted forecast, and cache status.
 type Result struct {
 	Location geocoding.Location `json:"location"`
@@ -24,27 +35,82 @@ type Service struct {
 	geocoder     geocoding.Geocoder
 	pollenClient pollen.PollenClient
 	cache        *cache.Cache
+	language     string
+	zones        timezone.Resolver
+	now          func() time.Time
+	log          *slog.Logger
 }
 
 // NewService creates a new forecast service.
 func NewService(g geocoding.Geocoder, p pollen.PollenClient, c *cache.Cache) *Service {
-	return &Service{geocoder: g, pollenClient: p, cache: c}
+	return &Service{
+		geocoder:     g,
+		pollenClient: p,
+		cache:        c,
+		language:     i18n.Default,
+		zones:        timezone.Embedded{},
+		now:          time.Now,
+		log:          logging.Discard,
+	}
+}
+
+// WithTimeZones sets how a location's time zone is found. A nil r keeps
+// the current resolver, which by default uses the embedded boundaries.
+func (s *Service) WithTimeZones(r timezone.Resolver) *Service {
+	if r != nil {
+		s.zones = r
+	}
+	return s
+}
+
+// WithLanguage sets the language the clients were configured with, so
+// cached answers in one language are not served for another. An empty lang
+// keeps the current one.
+func (s *Service) WithLanguage(lang string) *Service {
+	if lang != "" {
+		s.language = lang
+	}
+	return s
+}
+
+// WithLogger sets the logger. A nil l keeps the current one.
+func (s *Service) WithLogger(l *slog.Logger) *Service {
+	if l != nil {
+		s.log = l
+	}
+	return s
 }
 
 // GetForecast takes a ZIP code and days, performs geocoding, fetches pollen data,
 // formats it, and returns the result.
 func (s *Service) GetForecast(ctx context.Context, zipCode string, days int) (*Result, error) {
+	ctx, span := tracer.Start(ctx, "forecast.GetForecast", trace.WithAttributes(
+		attribute.String("pollenow.zip", zipCode),
+		attribute.Int("pollenow.days", days),
+	))
+	defer span.End()
+
+	result, err := s.getForecast(ctx, zipCode, days)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
+}
+
+func (s *Service) getForecast(ctx context.Context, zipCode string, days int) (*Result, error) {
+	span := trace.SpanFromContext(ctx)
+
 	// Check cache first
-	cacheKey := cache.Key(zipCode, days)
-	if s.cache != nil {
-		if data, age, ok := s.cache.Get(cacheKey); ok {
-			var result Result
-			if err := json.Unmarshal(data, &result); err == nil {
-				result.Cached = true
-				result.CacheAge = age
-				return &result, nil
-			}
-		}
+	cacheKey := cache.Key(zipCode, days, s.language)
+	if s.cache == nil {
+		span.SetAttributes(attribute.String("pollenow.cache", "disabled"))
+	} else if result, ok := s.cached(ctx, cacheKey); ok {
+		span.SetAttributes(attribute.String("pollenow.cache", "hit"))
+		s.log.Debug("forecast from cache", "zip", zipCode, "days", days, "age", result.CacheAge.Round(time.Second))
+		return result, nil
+	} else {
+		span.SetAttributes(attribute.String("pollenow.cache", "miss"))
 	}
 
 	// Geocode the ZIP code
@@ -52,6 +118,8 @@ func (s *Service) GetForecast(ctx context.Context, zipCode string, days int) (*R
 	if err != nil {
 		return nil, fmt.Errorf("geocoding ZIP %s: %w", zipCode, err)
 	}
+	loc.TimeZone = s.timeZone(ctx, loc)
+	s.log.Debug("geocoded", "zip", zipCode, "location", loc.DisplayName, "lat", loc.Lat, "lng", loc.Lng, "tz", loc.TimeZone)
 
 	// Fetch pollen forecast
 	raw, err := s.pollenClient.GetForecast(ctx, loc.Lat, loc.Lng, days)
@@ -59,8 +127,8 @@ func (s *Service) GetForecast(ctx context.Context, zipCode string, days int) (*R
 		return nil, fmt.Errorf("fetching pollen forecast: %w", err)
 	}
 
-	// Format the data
-	forecast := pollen.FormatForecast(raw)
+	// Format the data, naming days as seen from the location
+	forecast := pollen.FormatForecast(raw, timezone.Today(loc.TimeZone, s.now()))
 
 	result := &Result{
 		Location: *loc,
@@ -70,8 +138,59 @@ func (s *Service) GetForecast(ctx context.Context, zipCode string, days int) (*R
 
 	// Store in cache
 	if s.cache != nil {
-		_ = s.cache.Set(cacheKey, result)
+		if err := s.cache.Set(cacheKey, result); err != nil {
+			s.log.Warn("caching forecast failed", "zip", zipCode, "err", err)
+		}
 	}
 
 	return result, nil
 }
+
+// cached looks key up in the cache, in a span of its own.
+func (s *Service) cached(ctx context.Context, key string) (*Result, bool) {
+	_, span := tracer.Start(ctx, "cache.Get", trace.WithAttributes(attribute.String("pollenow.cache.key", key)))
+	defer span.End()
+
+	data, age, ok := s.cache.Get(key)
+	var result Result
+	if ok && json.Unmarshal(data, &result) != nil {
+		ok = false
+	}
+	if ok && result.Forecast == nil {
+		ok = false
+	}
+	if !ok {
+		span.SetAttributes(attribute.Bool("pollenow.cache.hit", false))
+		return nil, false
+	}
+
+	// Resolve the zone again rather than trust the cached one, so entries
+	// from before zones were recorded, and a changed override, get the
+	// right one. Resolvers answer locally.
+	result.Location.TimeZone = s.timeZone(ctx, &result.Location)
+	// A forecast cached before midnight at the location starts with a day
+	// that is already over; fetch a fresh one.
+	today := timezone.Today(result.Location.TimeZone, s.now())
+	if days := result.Forecast.Days; len(days) > 0 && days[0].Date < today.Format(pollen.DateLayout) {
+		s.log.Debug("cache miss", "key", key, "reason", "stale", "first_day", days[0].Date, "tz", result.Location.TimeZone)
+		span.SetAttributes(attribute.Bool("pollenow.cache.hit", false))
+		return nil, false
+	}
+	result.Forecast.Relabel(today)
+
+	span.SetAttributes(attribute.Bool("pollenow.cache.hit", true))
+	result.Cached = true
+	result.CacheAge = age
+	return &result, true
+}
+
+// timeZone resolves loc's IANA time zone. Failures are logged and leave
+// it empty, so dates fall back to the machine's zone.
+func (s *Service) timeZone(ctx context.Context, loc *geocoding.Location) string {
+	tz, err := s.zones.TimeZone(ctx, loc.Lat, loc.Lng)
+	if err != nil {
+		s.log.Warn("resolving time zone failed", "lat", loc.Lat, "lng", loc.Lng, "err", err)
+		return ""
+	}
+	return tz
+}
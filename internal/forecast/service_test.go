@@ -3,10 +3,20 @@ package forecast
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/shunito/pollenow/internal/cache"
 	"github.com/shunito/pollenow/internal/geocoding"
 	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/timezone"
 )
 
 type mockGeocoder struct {
@@ -94,3 +104,129 @@ func TestGetForecastPollenError(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+func TestGetForecastSpans(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	geo := &mockGeocoder{location: &geocoding.Location{Lat: 37.44, Lng: -122.14, DisplayName: "Test"}}
+	pc := &mockPollenClient{response: &pollen.RawForecastResponse{RegionCode: "US"}}
+	svc := NewService(geo, pc, cache.New(t.TempDir()))
+
+	for range 2 {
+		if _, err := svc.GetForecast(context.Background(), "94025", 3); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var statuses []string
+	for _, s := range exp.GetSpans() {
+		if s.Name != "forecast.GetForecast" {
+			continue
+		}
+		attrs := map[attribute.Key]attribute.Value{}
+		for _, a := range s.Attributes {
+			attrs[a.Key] = a.Value
+		}
+		if attrs["pollenow.zip"].AsString() != "94025" || attrs["pollenow.days"].AsInt64() != 3 {
+			t.Errorf("span attributes: %v", s.Attributes)
+		}
+		statuses = append(statuses, attrs["pollenow.cache"].AsString())
+	}
+	if strings.Join(statuses, ",") != "miss,hit" {
+		t.Errorf("cache statuses: got %v, want [miss hit]", statuses)
+	}
+
+	var lookups int
+	for _, s := range exp.GetSpans() {
+		if s.Name == "cache.Get" {
+			lookups++
+		}
+	}
+	if lookups != 2 {
+		t.Errorf("got %d cache.Get spans, want 2", lookups)
+	}
+}
+
+func TestGetForecastTimeZone(t *testing.T) {
+	geo := &mockGeocoder{location: &geocoding.Location{Lat: 37.44, Lng: -122.14, DisplayName: "Menlo Park, CA 94025"}}
+	pc := &mockPollenClient{response: &pollen.RawForecastResponse{
+		RegionCode: "US",
+		DailyInfo: []pollen.DailyInfo{
+			{Date: pollen.DateInfo{Year: 2025, Month: 6, Day: 15}},
+			{Date: pollen.DateInfo{Year: 2025, Month: 6, Day: 16}},
+		},
+	}}
+	svc := NewService(geo, pc, cache.New(t.TempDir()))
+
+	// 05:00 UTC on June 16 is still the evening of June 15 in Menlo Park.
+	now := time.Date(2025, 6, 16, 5, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	result, err := svc.GetForecast(context.Background(), "94025", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Location.TimeZone != "America/Los_Angeles" {
+		t.Errorf("TimeZone: got %q", result.Location.TimeZone)
+	}
+	if got := result.Forecast.Days[0].DayName; got != "Today" {
+		t.Errorf("first day: got %q, want Today", got)
+	}
+
+	// An hour later it is still June 15 there, so the cached copy holds.
+	now = now.Add(time.Hour)
+	if result, err = svc.GetForecast(context.Background(), "94025", 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Cached {
+		t.Error("expected a cache hit before local midnight")
+	}
+
+	// Past local midnight the cached forecast starts with yesterday.
+	now = now.Add(2 * time.Hour)
+	if result, err = svc.GetForecast(context.Background(), "94025", 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Cached {
+		t.Error("expected a stale cache entry to be refetched")
+	}
+	if got := result.Forecast.Days[0].DayName; got != "Yesterday" {
+		t.Errorf("first day after midnight: got %q, want Yesterday", got)
+	}
+}
+
+func TestGetForecastTimeZoneOverride(t *testing.T) {
+	geo := &mockGeocoder{location: &geocoding.Location{Lat: 37.44, Lng: -122.14, DisplayName: "Menlo Park, CA 94025"}}
+	pc := &mockPollenClient{response: &pollen.RawForecastResponse{
+		RegionCode: "US",
+		DailyInfo: []pollen.DailyInfo{
+			{Date: pollen.DateInfo{Year: 2025, Month: 6, Day: 15}},
+			{Date: pollen.DateInfo{Year: 2025, Month: 6, Day: 16}},
+		},
+	}}
+	c := cache.New(t.TempDir())
+	now := func() time.Time { return time.Date(2025, 6, 15, 20, 0, 0, 0, time.UTC) }
+
+	svc := NewService(geo, pc, c)
+	svc.now = now
+	if _, err := svc.GetForecast(context.Background(), "94025", 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 20:00 UTC is already June 16 in Tokyo; the override applies to the
+	// cached copy too.
+	svc = NewService(geo, pc, c).WithTimeZones(timezone.Fixed("Asia/Tokyo"))
+	svc.now = now
+	result, err := svc.GetForecast(context.Background(), "94025", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Location.TimeZone != "Asia/Tokyo" {
+		t.Errorf("TimeZone: got %q, want the override", result.Location.TimeZone)
+	}
+	if got := result.Forecast.Days[1].DayName; got != "Today" {
+		t.Errorf("second day: got %q, want Today", got)
+	}
+}
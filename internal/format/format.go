@@ -0,0 +1,127 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/shunito/pollenow/internal/forecast"
+	"github.com/shunito/pollenow/internal/i18n"
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/timezone"
+)
+
+// Options supply what the template functions need. Zero values fall back
+// to the default thresholds, English, plain text, and the current time.
+type Options struct {
+	Thresholds pollen.Thresholds
+	Messages   *i18n.Printer
+	// Color renders text in the color for level. Nil leaves text plain.
+	Color func(level pollen.PollenLevel, text string) string
+	Now   func() time.Time
+}
+
+// Template is a user-supplied text/template evaluated against a
+// forecast.Result, as in --format '{{.Location.DisplayName}}'.
+type Template struct {
+	tmpl *template.Template
+	opts Options
+}
+
+// Parse parses text with the template functions available.
+func Parse(name, text string, opts Options) (*Template, error) {
+	if opts.Thresholds == (pollen.Thresholds{}) {
+		opts.Thresholds = pollen.DefaultThresholds
+	}
+	if opts.Messages == nil {
+		opts.Messages = i18n.English
+	}
+	if opts.Color == nil {
+		opts.Color = func(_ pollen.PollenLevel, text string) string { return text }
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	t := &Template{opts: opts}
+	tmpl, err := template.New(name).Funcs(t.funcs(nil)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing format: %w", err)
+	}
+	t.tmpl = tmpl
+	return t, nil
+}
+
+// Check reports whether text parses as a format template. Errors refer
+// to it by name.
+func Check(name, text string) error {
+	_, err := Parse(name, text, Options{})
+	return err
+}
+
+// Execute writes the template applied to result, ending with a newline
+// if the template doesn't.
+func (t *Template) Execute(w io.Writer, result *forecast.Result) error {
+	// Clone so functions bound to this result don't leak into other calls.
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Funcs(t.funcs(result)).Execute(&buf, result); err != nil {
+		return fmt.Errorf("executing format: %w", err)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// funcs returns the template functions. Those that depend on the forecast,
+// such as today and day, see result; it is nil while parsing.
+func (t *Template) funcs(result *forecast.Result) template.FuncMap {
+	th := t.opts.Thresholds
+	return template.FuncMap{
+		"emoji": emoji,
+		"color": t.opts.Color,
+		"high":  th.IsHigh,
+		"low":   th.IsLow,
+		"alert": func(day pollen.DayForecast) *pollen.TypeLevel {
+			if tl, ok := th.Alert(day); ok {
+				return &tl
+			}
+			return nil
+		},
+		"today": func() *pollen.DayForecast {
+			if result == nil || len(result.Forecast.Days) == 0 {
+				return nil
+			}
+			return &result.Forecast.Days[0]
+		},
+		"day": func(day pollen.DayForecast) string {
+			date, err := time.Parse(pollen.DateLayout, day.Date)
+			if result == nil || err != nil {
+				return day.DayName
+			}
+			return t.opts.Messages.DayName(date, timezone.Today(result.Location.TimeZone, t.opts.Now()))
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"join":  strings.Join,
+	}
+}
+
+// levelEmoji marks Universal Pollen Index values 0 (None) through 5.
+var levelEmoji = []string{"⚪", "🟢", "🟢", "🟡", "🟠", "🔴"}
+
+// emoji returns a colored circle for level, or "❔" without data.
+func emoji(level pollen.PollenLevel) string {
+	if level.Level == nil || *level.Level < 0 || *level.Level >= len(levelEmoji) {
+		return "❔"
+	}
+	return levelEmoji[*level.Level]
+}
@@ -0,0 +1,85 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shunito/pollenow/internal/forecast"
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/i18n"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+func level(v int, cat string) pollen.PollenLevel {
+	return pollen.PollenLevel{Level: &v, Category: cat}
+}
+
+func testResult() *forecast.Result {
+	return &forecast.Result{
+		Location: geocoding.Location{DisplayName: "Menlo Park, CA 94025, USA", TimeZone: "America/Los_Angeles"},
+		Forecast: &pollen.Forecast{Days: []pollen.DayForecast{
+			{Date: "2025-06-15", DayName: "Today", Grass: level(2, "Low"), Tree: level(4, "High"), Weed: level(0, "None")},
+			{Date: "2025-06-16", DayName: "Tomorrow", Grass: level(1, "Very Low"), Tree: level(3, "Moderate")},
+		}},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	// Early on June 16 in UTC, still June 15 in Menlo Park.
+	now := func() time.Time { return time.Date(2025, 6, 16, 3, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name, text, want string
+	}{
+		{"fields", `{{.Location.DisplayName}}: tree {{(index .Forecast.Days 0).Tree.Category}}`, "Menlo Park, CA 94025, USA: tree High\n"},
+		{"emoji", `{{with today}}{{emoji .Grass}}{{emoji .Tree}}{{emoji .Weed}}{{end}}`, "🟢🟠⚪\n"},
+		{"thresholds", `{{range .Forecast.Days}}{{if high .Tree}}!{{else if low .Tree}}.{{else}}~{{end}}{{end}}`, "!~\n"},
+		{"alert", `{{with alert (today)}}{{upper .Name}} {{.Level.Level}}{{else}}clear{{end}}`, "TREE 4\n"},
+		{"day names", `{{range .Forecast.Days}}{{day .}} {{end}}`, "Today Tomorrow \n"},
+		{"newline kept", "{{.Location.TimeZone}}\n", "America/Los_Angeles\n"},
+	}
+	for _, tt := range tests {
+		tmpl, err := Parse(tt.name, tt.text, Options{Now: now})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, testResult()); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, b.String(), tt.want)
+		}
+	}
+}
+
+func TestExecuteOptions(t *testing.T) {
+	opts := Options{
+		Thresholds: pollen.Thresholds{High: 3, Low: 1},
+		Messages:   i18n.New("ja"),
+		Color:      func(l pollen.PollenLevel, s string) string { return "<" + s + ">" },
+		Now:        func() time.Time { return time.Date(2025, 6, 16, 12, 0, 0, 0, time.UTC) },
+	}
+	tmpl, err := Parse("opts", `{{range .Forecast.Days}}{{day .}}:{{if high .Tree}}{{color .Tree .Tree.Category}}{{end}} {{end}}`, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, testResult()); err != nil {
+		t.Fatal(err)
+	}
+	if want := "昨日:<High> 今日:<Moderate> \n"; b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check("test", `{{emoji (today).Tree}} {{day (today)}}`); err != nil {
+		t.Errorf("valid template: %v", err)
+	}
+	for _, text := range []string{`{{.Location`, `{{nosuchfunc .}}`} {
+		if err := Check("test", text); err == nil {
+			t.Errorf("Check(%q): expected an error", text)
+		}
+	}
+}
@@ -5,18 +5,50 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"regexp"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/shunito/pollenow/internal/apikey"
+	"github.com/shunito/pollenow/internal/httpx"
+	"github.com/shunito/pollenow/internal/logging"
 )
 
+var tracer = otel.Tracer("github.com/shunito/pollenow/internal/geocoding")
+
 var (
 	ErrInvalidZIP = errors.New("invalid ZIP code format")
 	ErrNoResults  = errors.New("no geocoding results found for ZIP code")
 )
 
+// APIError is returned when the Geocoding API answers but rejects the
+// request, e.g. because the key is invalid or the API is not enabled.
+type APIError struct {
+	Status  string // "REQUEST_DENIED", "OVER_QUERY_LIMIT", ...
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("geocoding error: %s (%s)", e.Message, e.Status)
+}
+
+// DefaultBaseURL is the Google Maps Geocoding API endpoint.
+const DefaultBaseURL = "https://maps.googleapis.com/maps/api/geocode/json"
+
 var zipRegex = regexp.MustCompile(`^\d{5}$`)
 
+// ValidZIP reports whether s is a 5-digit US ZIP code.
+func ValidZIP(s string) bool {
+	return zipRegex.MatchString(s)
+}
+
 // Geocoder converts a ZIP code into a Location.
 type Geocoder interface {
 	Geocode(ctx context.Context, zipCode string) (*Location, error)
@@ -29,41 +61,103 @@ type HTTPClient interface {
 
 // GoogleGeocoder implements Geocoder using the Google Maps Geocoding API.
 type GoogleGeocoder struct {
-	apiKey     string
+	key        apikey.Source
 	httpClient HTTPClient
+	baseURL    string
+	language   string
+	log        *slog.Logger
 }
 
 // NewGoogleGeocoder creates a new GoogleGeocoder.
 func NewGoogleGeocoder(apiKey string, client ...HTTPClient) *GoogleGeocoder {
+	return NewGoogleGeocoderWithSource(apikey.Static(apiKey), client...)
+}
+
+// NewGoogleGeocoderWithSource creates a GoogleGeocoder that resolves its
+// API key from src only when a request is made.
+func NewGoogleGeocoderWithSource(src apikey.Source, client ...HTTPClient) *GoogleGeocoder {
 	var c HTTPClient = http.DefaultClient
 	if len(client) > 0 && client[0] != nil {
 		c = client[0]
 	}
-	return &GoogleGeocoder{apiKey: apiKey, httpClient: c}
+	return &GoogleGeocoder{key: src, httpClient: c, baseURL: DefaultBaseURL, log: logging.Discard}
+}
+
+// WithBaseURL points the geocoder at another endpoint, such as an internal
+// gateway or a mock server. An empty u keeps the current one.
+func (g *GoogleGeocoder) WithBaseURL(u string) *GoogleGeocoder {
+	if u != "" {
+		g.baseURL = u
+	}
+	return g
+}
+
+// WithLanguage requests place names in lang, a BCP 47 tag such as "ja".
+// An empty lang keeps the current one; by default Google picks.
+func (g *GoogleGeocoder) WithLanguage(lang string) *GoogleGeocoder {
+	if lang != "" {
+		g.language = lang
+	}
+	return g
+}
+
+// WithLogger sets the logger for requests. A nil l keeps the current one.
+func (g *GoogleGeocoder) WithLogger(l *slog.Logger) *GoogleGeocoder {
+	if l != nil {
+		g.log = l
+	}
+	return g
 }
 
 // Geocode converts a US ZIP code to a Location with coordinates and display name.
 func (g *GoogleGeocoder) Geocode(ctx context.Context, zipCode string) (*Location, error) {
-	if !zipRegex.MatchString(zipCode) {
+	ctx, span := tracer.Start(ctx, "geocoding.Geocode", trace.WithAttributes(attribute.String("pollenow.zip", zipCode)))
+	defer span.End()
+
+	loc, err := g.geocode(ctx, zipCode)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return loc, err
+}
+
+func (g *GoogleGeocoder) geocode(ctx context.Context, zipCode string) (*Location, error) {
+	if !ValidZIP(zipCode) {
 		return nil, fmt.Errorf("%w: %q", ErrInvalidZIP, zipCode)
 	}
 
-	u := fmt.Sprintf(
-		"https://maps.googleapis.com/maps/api/geocode/json?address=%s&key=%s",
-		url.QueryEscape(zipCode),
-		url.QueryEscape(g.apiKey),
-	)
+	key, err := g.key.Key(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(g.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid geocoding base URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("address", zipCode)
+	if g.language != "" {
+		q.Set("language", g.language)
+	}
+	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating geocoding request: %w", err)
 	}
+	req.Header.Set(apikey.Header, key)
 
+	start := time.Now()
 	resp, err := g.httpClient.Do(req)
 	if err != nil {
+		g.log.Info("api request", "api", "geocoding", "url", httpx.RedactURL(req.URL), "latency", time.Since(start), "err", err)
 		return nil, fmt.Errorf("geocoding request failed: %w", err)
 	}
 	defer resp.Body.Close()
+	g.log.Info("api request", "api", "geocoding", "url", httpx.RedactURL(req.URL), "status", resp.StatusCode, "latency", time.Since(start))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("geocoding API returned status %d", resp.StatusCode)
@@ -76,7 +170,7 @@ func (g *GoogleGeocoder) Geocode(ctx context.Context, zipCode string) (*Location
 
 	if data.Status != "OK" {
 		if data.ErrorMessage != "" {
-			return nil, fmt.Errorf("geocoding error: %s (%s)", data.ErrorMessage, data.Status)
+			return nil, &APIError{Status: data.Status, Message: data.ErrorMessage}
 		}
 		return nil, fmt.Errorf("%w: status %s", ErrNoResults, data.Status)
 	}
@@ -12,9 +12,11 @@ import (
 type mockHTTPClient struct {
 	response *http.Response
 	err      error
+	req      *http.Request // last request sent
 }
 
 func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.req = req
 	return m.response, m.err
 }
 
@@ -105,3 +107,49 @@ func TestGeocodeNetworkError(t *testing.T) {
 		t.Error("expected error for network failure")
 	}
 }
+
+func TestGeocodeBaseURL(t *testing.T) {
+	client := &mockHTTPClient{err: errors.New("stop")}
+
+	g := NewGoogleGeocoder("test-key", client).WithBaseURL("http://gateway.internal:8080/geocode/json")
+	g.Geocode(context.Background(), "94025")
+
+	u := client.req.URL
+	if u.Host != "gateway.internal:8080" || u.Path != "/geocode/json" {
+		t.Errorf("request went to %s%s", u.Host, u.Path)
+	}
+	if u.Query().Get("address") != "94025" {
+		t.Errorf("address: got %q", u.Query().Get("address"))
+	}
+
+	NewGoogleGeocoder("test-key", client).WithBaseURL("").Geocode(context.Background(), "94025")
+	if client.req.URL.Host != "maps.googleapis.com" {
+		t.Errorf("empty base URL should keep the default, got %s", client.req.URL.Host)
+	}
+}
+
+func TestGeocodeLanguage(t *testing.T) {
+	client := &mockHTTPClient{err: errors.New("stop")}
+
+	NewGoogleGeocoder("test-key", client).Geocode(context.Background(), "94025")
+	if client.req.URL.Query().Has("language") {
+		t.Errorf("language should be omitted by default, got %s", client.req.URL.RawQuery)
+	}
+
+	NewGoogleGeocoder("test-key", client).WithLanguage("ja").Geocode(context.Background(), "94025")
+	if got := client.req.URL.Query().Get("language"); got != "ja" {
+		t.Errorf("language: got %q", got)
+	}
+}
+
+func TestGeocodeKeyHeader(t *testing.T) {
+	client := &mockHTTPClient{err: errors.New("stop")}
+	NewGoogleGeocoder("test-key", client).Geocode(context.Background(), "94025")
+
+	if got := client.req.Header.Get("X-Goog-Api-Key"); got != "test-key" {
+		t.Errorf("X-Goog-Api-Key: got %q", got)
+	}
+	if strings.Contains(client.req.URL.String(), "test-key") {
+		t.Errorf("key leaked into the URL: %s", client.req.URL)
+	}
+}
@@ -5,6 +5,7 @@ type Location struct {
 	Lat         float64 `json:"lat"`
 	Lng         float64 `json:"lng"`
 	DisplayName string  `json:"displayName"`
+	TimeZone    string  `json:"timeZone,omitempty"` // IANA name, e.g. "America/Los_Angeles"; set by the forecast service
 }
 
 // googleGeocodingResponse mirrors the Google Maps Geocoding API JSON response.
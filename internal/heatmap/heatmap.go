@@ -0,0 +1,164 @@
+package heatmap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+var (
+	ErrInvalidRadius = errors.New("invalid map radius")
+	ErrInvalidZoom   = errors.New("invalid map zoom")
+)
+
+// TileSize is the width and height of a heatmap tile in pixels.
+const TileSize = 256
+
+// MaxRadius bounds how many tiles around the center are fetched; 3 is a
+// 7x7 grid, or 49 requests.
+const MaxRadius = 3
+
+// Background is painted under the mostly transparent tiles.
+var Background = color.RGBA{R: 0x1c, G: 0x1c, B: 0x1c, A: 0xff}
+
+// TileFetcher fetches one PNG heatmap tile.
+type TileFetcher interface {
+	HeatmapTile(ctx context.Context, mapType string, zoom, x, y int) ([]byte, error)
+}
+
+// Map is a stitched heatmap with the requested location marked.
+type Map struct {
+	Image  *image.RGBA // opaque, tiles composited over Background
+	Marker image.Point // the location, in Image pixels
+}
+
+// TileXY returns the fractional web mercator tile coordinates of lat, lng
+// at zoom. The integer parts name the tile; the fractions locate the point
+// within it.
+func TileXY(lat, lng float64, zoom int) (x, y float64) {
+	n := math.Exp2(float64(zoom))
+	// Mercator is undefined at the poles; tile grids stop at ±85.0511°.
+	lat = math.Max(-85.0511, math.Min(85.0511, lat))
+	rad := lat * math.Pi / 180
+	x = (lng + 180) / 360 * n
+	y = (1 - math.Log(math.Tan(rad)+1/math.Cos(rad))/math.Pi) / 2 * n
+	return x, y
+}
+
+// CheckBounds reports whether zoom and radius are within the ranges Stitch
+// accepts.
+func CheckBounds(zoom, radius int) error {
+	if zoom < 0 || zoom > pollen.MaxZoom {
+		return fmt.Errorf("%w: must be between 0 and %d", ErrInvalidZoom, pollen.MaxZoom)
+	}
+	if radius < 0 || radius > MaxRadius {
+		return fmt.Errorf("%w: must be between 0 and %d", ErrInvalidRadius, MaxRadius)
+	}
+	return nil
+}
+
+// Stitch fetches the (2*radius+1)² tiles centered on lat, lng and joins
+// them into one image. Columns wrap across the antimeridian; rows beyond
+// the poles are left as background.
+func Stitch(ctx context.Context, f TileFetcher, mapType string, lat, lng float64, zoom, radius int) (*Map, error) {
+	if err := CheckBounds(zoom, radius); err != nil {
+		return nil, err
+	}
+	fx, fy := TileXY(lat, lng, zoom)
+	cx, cy := int(fx), int(fy)
+	n := 1 << zoom
+	side := (2*radius + 1) * TileSize
+
+	img := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(img, img.Bounds(), image.NewUniform(Background), image.Point{}, draw.Src)
+
+	for row := -radius; row <= radius; row++ {
+		ty := cy + row
+		if ty < 0 || ty >= n {
+			continue
+		}
+		for col := -radius; col <= radius; col++ {
+			tx := ((cx+col)%n + n) % n
+			data, err := f.HeatmapTile(ctx, mapType, zoom, tx, ty)
+			if err != nil {
+				return nil, err
+			}
+			tile, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("decoding tile %d/%d/%d: %w", zoom, tx, ty, err)
+			}
+			at := image.Pt((col+radius)*TileSize, (row+radius)*TileSize)
+			draw.Draw(img, image.Rectangle{Min: at, Max: at.Add(image.Pt(TileSize, TileSize))}, tile, tile.Bounds().Min, draw.Over)
+		}
+	}
+
+	marker := image.Pt(
+		int((fx-float64(cx-radius))*TileSize),
+		int((fy-float64(cy-radius))*TileSize),
+	)
+	return &Map{Image: img, Marker: marker}, nil
+}
+
+// Scale returns a nearest-neighbor copy of m resized to w x h, with the
+// marker moved to match.
+func (m *Map) Scale(w, h int) *Map {
+	src := m.Image.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy := src.Min.Y + y*src.Dy()/h
+		for x := 0; x < w; x++ {
+			sx := src.Min.X + x*src.Dx()/w
+			dst.SetRGBA(x, y, m.Image.RGBAAt(sx, sy))
+		}
+	}
+	return &Map{
+		Image:  dst,
+		Marker: image.Pt(m.Marker.X*w/src.Dx(), m.Marker.Y*h/src.Dy()),
+	}
+}
+
+// Marked returns a copy of the image with a crosshair over the marker,
+// sized to stay visible at the image's resolution.
+func (m *Map) Marked() *image.RGBA {
+	b := m.Image.Bounds()
+	img := image.NewRGBA(b)
+	draw.Draw(img, b, m.Image, b.Min, draw.Src)
+
+	arm := max(2, min(b.Dx(), b.Dy())/24)
+	thick := max(0, arm/6)
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	black := color.RGBA{A: 0xff}
+	p := m.Marker
+	// A dark outline keeps the cross readable over light tiles.
+	fill(img, image.Rect(p.X-arm-1, p.Y-thick-1, p.X+arm+2, p.Y+thick+2), black)
+	fill(img, image.Rect(p.X-thick-1, p.Y-arm-1, p.X+thick+2, p.Y+arm+2), black)
+	fill(img, image.Rect(p.X-arm, p.Y-thick, p.X+arm+1, p.Y+thick+1), white)
+	fill(img, image.Rect(p.X-thick, p.Y-arm, p.X+thick+1, p.Y+arm+1), white)
+	return img
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r.Intersect(img.Bounds()), image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// SavePNG writes the marked map to path.
+func (m *Map) SavePNG(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	if err := png.Encode(f, m.Marked()); err != nil {
+		f.Close()
+		return fmt.Errorf("encoding PNG: %w", err)
+	}
+	return f.Close()
+}
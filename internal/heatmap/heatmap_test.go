@@ -0,0 +1,219 @@
+package heatmap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+type mockFetcher struct {
+	tiles []string
+	err   error
+}
+
+func (m *mockFetcher) HeatmapTile(ctx context.Context, mapType string, zoom, x, y int) ([]byte, error) {
+	m.tiles = append(m.tiles, fmt.Sprintf("%s/%d/%d/%d", mapType, zoom, x, y))
+	if m.err != nil {
+		return nil, m.err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, TileSize, TileSize))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes(), nil
+}
+
+func TestTileXY(t *testing.T) {
+	tests := []struct {
+		lat, lng float64
+		zoom     int
+		x, y     float64
+	}{
+		{0, 0, 0, 0.5, 0.5},
+		{0, 0, 1, 1, 1},
+		{0, -180, 2, 0, 2},
+		{37.4529, -122.1817, 8, 41.12, 99.24}, // Menlo Park
+	}
+	for _, tt := range tests {
+		x, y := TileXY(tt.lat, tt.lng, tt.zoom)
+		if math.Abs(x-tt.x) > 0.01 || math.Abs(y-tt.y) > 0.01 {
+			t.Errorf("TileXY(%v, %v, %d) = %.2f, %.2f, want %.2f, %.2f", tt.lat, tt.lng, tt.zoom, x, y, tt.x, tt.y)
+		}
+	}
+}
+
+func TestStitch(t *testing.T) {
+	f := &mockFetcher{}
+	m, err := Stitch(context.Background(), f, "TREE_UPI", 37.4529, -122.1817, 8, 1)
+	if err != nil {
+		t.Fatalf("Stitch failed: %v", err)
+	}
+	if len(f.tiles) != 9 || f.tiles[0] != "TREE_UPI/8/40/98" || f.tiles[8] != "TREE_UPI/8/42/100" {
+		t.Errorf("tiles: got %v", f.tiles)
+	}
+	if got := m.Image.Bounds().Size(); got != image.Pt(768, 768) {
+		t.Errorf("size: got %v", got)
+	}
+	// 41.12, 99.24 is 0.12, 0.24 into the center tile.
+	if m.Marker.X != 256+29 || m.Marker.Y != 256+61 {
+		t.Errorf("marker: got %v", m.Marker)
+	}
+}
+
+func TestStitchWrapsAntimeridian(t *testing.T) {
+	f := &mockFetcher{}
+	if _, err := Stitch(context.Background(), f, "TREE_UPI", 0, 179.9, 2, 1); err != nil {
+		t.Fatalf("Stitch failed: %v", err)
+	}
+	if f.tiles[2] != "TREE_UPI/2/0/1" {
+		t.Errorf("expected the east column to wrap to x=0, got %v", f.tiles)
+	}
+}
+
+func TestStitchErrors(t *testing.T) {
+	f := &mockFetcher{err: errors.New("boom")}
+	if _, err := Stitch(context.Background(), f, "TREE_UPI", 0, 0, 2, 1); err == nil || err.Error() != "boom" {
+		t.Errorf("expected fetch error, got %v", err)
+	}
+	if _, err := Stitch(context.Background(), f, "TREE_UPI", 0, 0, 2, MaxRadius+1); !errors.Is(err, ErrInvalidRadius) {
+		t.Errorf("expected ErrInvalidRadius, got %v", err)
+	}
+	for _, zoom := range []int{-1, pollen.MaxZoom + 1} {
+		if _, err := Stitch(context.Background(), f, "TREE_UPI", 0, 0, zoom, 1); !errors.Is(err, ErrInvalidZoom) {
+			t.Errorf("zoom %d: expected ErrInvalidZoom, got %v", zoom, err)
+		}
+	}
+}
+
+func testMap() *Map {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+3] = 0xff, 0xff
+	}
+	return &Map{Image: img, Marker: image.Pt(32, 32)}
+}
+
+func TestMarked(t *testing.T) {
+	m := testMap()
+	img := m.Marked()
+	if got := img.RGBAAt(32, 32); got != (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+		t.Errorf("marker center: got %v", got)
+	}
+	if got := m.Image.RGBAAt(32, 32); got.G != 0 {
+		t.Error("Marked modified the original image")
+	}
+}
+
+func TestRenderBlocks(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, testMap(), ProtocolBlocks, 20); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("got %d lines, want 10", len(lines))
+	}
+	if got := strings.Count(lines[0], "▀"); got != 20 {
+		t.Errorf("got %d cells, want 20", got)
+	}
+	if !strings.Contains(lines[0], "\x1b[38;2;255;0;0m") {
+		t.Errorf("expected truecolor red, got %q", lines[0])
+	}
+}
+
+func TestRenderKitty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, testMap(), ProtocolKitty, 20); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1b_Ga=T,f=100,q=2,c=20,m=0;") || !strings.HasSuffix(out, "\x1b\\\n") {
+		t.Errorf("unexpected kitty output %q", out)
+	}
+}
+
+func TestRenderSixel(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, testMap(), ProtocolSixel, 4); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1bPq\"1;1;32;32") || !strings.HasSuffix(out, "\x1b\\\n") {
+		t.Errorf("unexpected sixel framing %q", out)
+	}
+	// Red is cube color 5*36 = 180, defined as 100% red.
+	if !strings.Contains(out, "#180;2;100;0;0") {
+		t.Errorf("expected red in the palette, got %q", out)
+	}
+	if got := strings.Count(out, "-"); got != 6 {
+		t.Errorf("got %d bands, want 6 for 32 rows", got)
+	}
+}
+
+func TestRenderUnknown(t *testing.T) {
+	if err := Render(&bytes.Buffer{}, testMap(), "ascii-art", 20); !errors.Is(err, ErrUnknownProtocol) {
+		t.Errorf("expected ErrUnknownProtocol, got %v", err)
+	}
+}
+
+func TestCheckProtocol(t *testing.T) {
+	for _, p := range []string{ProtocolAuto, ProtocolBlocks, ProtocolKitty, ProtocolSixel} {
+		if err := CheckProtocol(p); err != nil {
+			t.Errorf("CheckProtocol(%q): %v", p, err)
+		}
+	}
+	if err := CheckProtocol("ascii-art"); !errors.Is(err, ErrUnknownProtocol) {
+		t.Errorf("expected ErrUnknownProtocol, got %v", err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{"TERM": "xterm-256color"}, ProtocolBlocks},
+		{map[string]string{"TERM": "xterm-kitty"}, ProtocolKitty},
+		{map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, ProtocolKitty},
+		{map[string]string{"TERM_PROGRAM": "WezTerm"}, ProtocolKitty},
+		{map[string]string{"TERM": "foot"}, ProtocolSixel},
+		{map[string]string{}, ProtocolBlocks},
+	}
+	for _, tt := range tests {
+		if got := Detect(func(k string) string { return tt.env[k] }); got != tt.want {
+			t.Errorf("Detect(%v) = %s, want %s", tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestSavePNG(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "map.png")
+	if err := testMap().SavePNG(p); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("not a PNG: %v", err)
+	}
+	if img.Bounds().Dx() != 64 {
+		t.Errorf("width: got %d", img.Bounds().Dx())
+	}
+}
@@ -0,0 +1,199 @@
+package heatmap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image/png"
+	"io"
+	"strings"
+)
+
+var (
+	ErrUnknownProtocol = errors.New("unknown render mode")
+)
+
+// Protocols for drawing the map in a terminal.
+const (
+	ProtocolAuto   = "auto"
+	ProtocolBlocks = "blocks" // half-block characters in 24-bit color; works almost anywhere
+	ProtocolKitty  = "kitty"  // kitty graphics protocol (kitty, WezTerm, Ghostty)
+	ProtocolSixel  = "sixel"  // DEC sixel graphics (foot, mlterm, contour, xterm -ti vt340)
+)
+
+// CheckProtocol reports whether p is a protocol Render accepts, or auto.
+func CheckProtocol(p string) error {
+	switch p {
+	case ProtocolAuto, ProtocolBlocks, ProtocolKitty, ProtocolSixel:
+		return nil
+	}
+	return unknownProtocol(p)
+}
+
+func unknownProtocol(p string) error {
+	return fmt.Errorf("%w %q (use %s, %s, %s, or %s)", ErrUnknownProtocol, p, ProtocolAuto, ProtocolBlocks, ProtocolKitty, ProtocolSixel)
+}
+
+// Detect picks a protocol from the terminal's environment. Terminals don't
+// advertise graphics support reliably, so anything unrecognized gets
+// blocks.
+func Detect(getenv func(string) string) string {
+	term, program := getenv("TERM"), getenv("TERM_PROGRAM")
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty",
+		program == "WezTerm", program == "ghostty":
+		return ProtocolKitty
+	case strings.Contains(term, "sixel"), strings.HasPrefix(term, "foot"),
+		strings.HasPrefix(term, "mlterm"), strings.HasPrefix(term, "contour"):
+		return ProtocolSixel
+	}
+	return ProtocolBlocks
+}
+
+// sixelCellWidth approximates a terminal cell's width in pixels, to size
+// sixel output to a column count.
+const sixelCellWidth = 8
+
+// Render draws m with protocol p, cols terminal columns wide.
+func Render(w io.Writer, m *Map, p string, cols int) error {
+	bw := bufio.NewWriter(w)
+	var err error
+	switch p {
+	case ProtocolBlocks:
+		err = renderBlocks(bw, m, cols)
+	case ProtocolKitty:
+		err = renderKitty(bw, m, cols)
+	case ProtocolSixel:
+		width := min(cols*sixelCellWidth, m.Image.Bounds().Dx())
+		err = renderSixel(bw, m.Scale(width, width*m.Image.Bounds().Dy()/m.Image.Bounds().Dx()))
+	default:
+		return unknownProtocol(p)
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// renderBlocks draws two pixels per cell: the upper as the foreground of
+// "▀", the lower as its background. Cells are about twice as tall as they
+// are wide, so the pixels come out square.
+func renderBlocks(w *bufio.Writer, m *Map, cols int) error {
+	b := m.Image.Bounds()
+	rows := max(1, cols*b.Dy()/b.Dx()/2)
+	img := m.Scale(cols, rows*2).Marked()
+	for y := 0; y < rows*2; y += 2 {
+		for x := 0; x < cols; x++ {
+			top, bottom := img.RGBAAt(x, y), img.RGBAAt(x, y+1)
+			fmt.Fprintf(w, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+		}
+		w.WriteString("\x1b[0m\n")
+	}
+	return nil
+}
+
+// kittyChunk is the largest payload the kitty protocol accepts per escape.
+const kittyChunk = 4096
+
+// renderKitty sends the full-resolution PNG and lets the terminal scale it
+// to cols cells.
+func renderKitty(w *bufio.Writer, m *Map, cols int) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, m.Marked()); err != nil {
+		return fmt.Errorf("encoding PNG: %w", err)
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+	for i := 0; i < len(data); i += kittyChunk {
+		end := min(i+kittyChunk, len(data))
+		more := 0
+		if end < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(w, "\x1b_Ga=T,f=100,q=2,c=%d,m=%d;%s\x1b\\", cols, more, data[i:end])
+		} else {
+			fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, data[i:end])
+		}
+	}
+	w.WriteString("\n")
+	return nil
+}
+
+// renderSixel quantizes to a 6x6x6 color cube and writes one sixel band
+// (six pixel rows) at a time, one pass per color present in the band.
+func renderSixel(w *bufio.Writer, m *Map) error {
+	img := m.Marked()
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+
+	idx := make([]uint8, width*height)
+	used := [216]bool{}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := img.RGBAAt(b.Min.X+x, b.Min.Y+y)
+			i := level(c.R)*36 + level(c.G)*6 + level(c.B)
+			idx[y*width+x] = uint8(i)
+			used[i] = true
+		}
+	}
+
+	fmt.Fprintf(w, "\x1bPq\"1;1;%d;%d", width, height)
+	for i, ok := range used {
+		if ok {
+			fmt.Fprintf(w, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+		}
+	}
+
+	line := make([]byte, width)
+	for top := 0; top < height; top += 6 {
+		var inBand [216]bool
+		for y := top; y < min(top+6, height); y++ {
+			for _, i := range idx[y*width : (y+1)*width] {
+				inBand[i] = true
+			}
+		}
+		for c, ok := range inBand {
+			if !ok {
+				continue
+			}
+			for x := 0; x < width; x++ {
+				var bits byte
+				for k := 0; k < 6 && top+k < height; k++ {
+					if int(idx[(top+k)*width+x]) == c {
+						bits |= 1 << k
+					}
+				}
+				line[x] = '?' + bits
+			}
+			fmt.Fprintf(w, "#%d", c)
+			writeRuns(w, line)
+			w.WriteByte('$')
+		}
+		w.WriteByte('-')
+	}
+	w.WriteString("\x1b\\\n")
+	return nil
+}
+
+// level maps an 8-bit channel to the nearest of six cube levels.
+func level(v uint8) int {
+	return (int(v)*5 + 127) / 255
+}
+
+// writeRuns writes sixel characters, run-length encoding repeats.
+func writeRuns(w *bufio.Writer, line []byte) {
+	for i := 0; i < len(line); {
+		j := i
+		for j < len(line) && line[j] == line[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(w, "!%d%c", n, line[i])
+		} else {
+			w.Write(line[i:j])
+		}
+		i = j
+	}
+}
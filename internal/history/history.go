@@ -0,0 +1,163 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/xdg"
+)
+
+// Record is one stored day of forecast data for a ZIP code.
+type Record struct {
+	ZIP        string             `json:"zip"`
+	Location   geocoding.Location `json:"location"`
+	Day        pollen.DayForecast `json:"day"`
+	Lead       int                `json:"lead"` // days ahead of the fetch; 0 means same-day
+	RecordedAt time.Time          `json:"recordedAt"`
+}
+
+// Store keeps per-location forecast history as JSON files.
+type Store struct {
+	dir string
+	err error // set when no data directory could be resolved
+}
+
+// New creates a Store. If dir is empty, uses $XDG_DATA_HOME/pollenow/history/.
+// If that cannot be resolved, every operation returns the resolution error.
+func New(dir string) *Store {
+	s := &Store{dir: dir}
+	if dir == "" {
+		data, err := xdg.DataDir()
+		s.dir, s.err = filepath.Join(data, "history"), err
+	}
+	return s
+}
+
+// Record stores every day of a forecast for the given ZIP code.
+// A day already on record is only replaced by data with the same or a
+// shorter lead time, so same-day readings win over earlier predictions.
+func (s *Store) Record(zip string, loc geocoding.Location, fc *pollen.Forecast) error {
+	if fc == nil || len(fc.Days) == 0 {
+		return nil
+	}
+
+	records, err := s.load(zip)
+	if err != nil {
+		return err
+	}
+
+	byDate := make(map[string]int, len(records))
+	for i, r := range records {
+		byDate[r.Day.Date] = i
+	}
+
+	now := time.Now()
+	for lead, day := range fc.Days {
+		rec := Record{ZIP: zip, Location: loc, Day: day, Lead: lead, RecordedAt: now}
+		if i, ok := byDate[day.Date]; ok {
+			if lead <= records[i].Lead {
+				records[i] = rec
+			}
+			continue
+		}
+		byDate[day.Date] = len(records)
+		records = append(records, rec)
+	}
+
+	return s.save(zip, records)
+}
+
+// Range returns the records for zip whose dates fall within [from, to],
+// sorted by date. Missing history is not an error.
+func (s *Store) Range(zip string, from, to time.Time) ([]Record, error) {
+	records, err := s.load(zip)
+	if err != nil {
+		return nil, err
+	}
+
+	lo, hi := from.Format(pollen.DateLayout), to.Format(pollen.DateLayout)
+	var out []Record
+	for _, r := range records {
+		if r.Day.Date >= lo && r.Day.Date <= hi {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// All returns every record stored for zip, sorted by date.
+func (s *Store) All(zip string) ([]Record, error) {
+	return s.load(zip)
+}
+
+func (s *Store) load(zip string) ([]Record, error) {
+	path, err := s.path(zip)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parsing history for %s: %w", zip, err)
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+func (s *Store) save(zip string, records []Record) error {
+	path, err := s.path(zip)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("creating history dir: %w", err)
+	}
+
+	sortRecords(records)
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("marshaling history: %w", err)
+	}
+
+	// Write to a temp file first so a crash never leaves a truncated history.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("writing history: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("writing history: %w", err)
+	}
+	return nil
+}
+
+// path returns the history file for zip. Only valid ZIP codes are used
+// as file names, so an argument like "../x" cannot leave the directory.
+func (s *Store) path(zip string) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	if !geocoding.ValidZIP(zip) {
+		return "", fmt.Errorf("%w: %q", geocoding.ErrInvalidZIP, zip)
+	}
+	return filepath.Join(s.dir, zip+".json"), nil
+}
+
+func sortRecords(records []Record) {
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Day.Date < records[j].Day.Date
+	})
+}
@@ -0,0 +1,105 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+func level(v int, cat string) pollen.PollenLevel {
+	return pollen.PollenLevel{Level: &v, Category: cat}
+}
+
+func TestRecordAndRange(t *testing.T) {
+	s := New(t.TempDir())
+	loc := geocoding.Location{DisplayName: "Menlo Park, CA 94025, USA"}
+
+	fc := &pollen.Forecast{Days: []pollen.DayForecast{
+		{Date: "2025-06-15", Tree: level(4, "High")},
+		{Date: "2025-06-16", Tree: level(3, "Moderate")},
+		{Date: "2025-06-17", Tree: level(2, "Low")},
+	}}
+	if err := s.Record("94025", loc, fc); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	from := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	records, err := s.Range("94025", from, to)
+	if err != nil {
+		t.Fatalf("Range failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[0].Day.Date != "2025-06-16" || records[1].Day.Date != "2025-06-17" {
+		t.Errorf("unexpected dates: %s, %s", records[0].Day.Date, records[1].Day.Date)
+	}
+	if records[0].Location.DisplayName != loc.DisplayName {
+		t.Errorf("Location: got %q", records[0].Location.DisplayName)
+	}
+}
+
+func TestRecordPrefersShorterLead(t *testing.T) {
+	s := New(t.TempDir())
+	loc := geocoding.Location{}
+
+	// Fetched on the 15th: the 16th is a one-day-ahead prediction.
+	first := &pollen.Forecast{Days: []pollen.DayForecast{
+		{Date: "2025-06-15", Tree: level(4, "High")},
+		{Date: "2025-06-16", Tree: level(3, "Moderate")},
+	}}
+	// Fetched on the 16th: same-day reading should replace the prediction.
+	second := &pollen.Forecast{Days: []pollen.DayForecast{
+		{Date: "2025-06-16", Tree: level(5, "Very High")},
+	}}
+	// A later fetch that only predicts the 16th again must not overwrite it.
+	stale := &pollen.Forecast{Days: []pollen.DayForecast{
+		{Date: "2025-06-14", Tree: level(1, "Very Low")},
+		{Date: "2025-06-16", Tree: level(2, "Low")},
+	}}
+
+	for _, fc := range []*pollen.Forecast{first, second, stale} {
+		if err := s.Record("94025", loc, fc); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	records, err := s.All("94025")
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	if records[0].Day.Date != "2025-06-14" {
+		t.Errorf("records not sorted: first date %s", records[0].Day.Date)
+	}
+	got := records[2].Day.Tree
+	if got.Level == nil || *got.Level != 5 {
+		t.Errorf("2025-06-16 Tree: got %v, want 5", got.Level)
+	}
+}
+
+func TestRangeMissing(t *testing.T) {
+	s := New(t.TempDir())
+	records, err := s.Range("10001", time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("Range should not fail for missing history: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("got %d records, want 0", len(records))
+	}
+}
+
+func TestInvalidZIP(t *testing.T) {
+	s := New(t.TempDir())
+	for _, zip := range []string{"../../etc/passwd", "1000", ""} {
+		if _, err := s.All(zip); !errors.Is(err, geocoding.ErrInvalidZIP) {
+			t.Errorf("All(%q): expected ErrInvalidZIP, got %v", zip, err)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrNotRecorded = errors.New("no recorded response")
+)
+
+// Redacted replaces API keys in recordings.
+const Redacted = "REDACTED"
+
+// secretParams and secretHeaders carry credentials and are redacted
+// before anything is written to disk.
+var (
+	secretParams  = []string{"key"}
+	secretHeaders = []string{"X-Goog-Api-Key", "Authorization"}
+)
+
+// Exchange is one recorded request and its response.
+type Exchange struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request with its credentials redacted.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as received, with any echoed credentials
+// redacted.
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// Recording is a Client that saves every exchange with the next Client to
+// a directory, one numbered JSON file per exchange. Failed round trips,
+// with no response, are not recorded. Numbering continues after any
+// recordings already there, so several clients and runs can share one
+// directory.
+type Recording struct {
+	next Client
+	dir  string
+}
+
+// NewRecording wraps next so exchanges are saved under dir.
+func NewRecording(next Client, dir string) *Recording {
+	return &Recording{next: next, dir: dir}
+}
+
+// Do sends req and records the exchange. The response body is read in
+// full and handed back to the caller unchanged.
+func (r *Recording) Do(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	resp, err := r.next.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	secrets := credentials(req)
+	ex := Exchange{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    RedactURL(req.URL),
+			Header: redactHeader(req.Header),
+			Body:   redactString(string(reqBody), secrets),
+		},
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: resp.Header.Clone(),
+			Body:   redactString(string(body), secrets),
+		},
+	}
+	for _, vs := range ex.Response.Header {
+		for i, v := range vs {
+			vs[i] = redactString(v, secrets)
+		}
+	}
+
+	if err := r.save(ex, req.URL.Hostname()); err != nil {
+		return nil, fmt.Errorf("recording exchange: %w", err)
+	}
+	return resp, nil
+}
+
+// save writes ex to the first free sequence number. Files are created
+// exclusively, so concurrent recorders never overwrite each other.
+func (r *Recording) save(ex Exchange, host string) error {
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return err
+	}
+	// Keep URLs readable: no \u0026 for every &.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(ex); err != nil {
+		return err
+	}
+
+	existing, _ := filepath.Glob(filepath.Join(r.dir, "*.json"))
+	for seq := len(existing) + 1; ; seq++ {
+		name := filepath.Join(r.dir, fmt.Sprintf("%04d-%s.json", seq, host))
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(buf.Bytes())
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	}
+}
+
+// Replay is a Client that answers from a directory written by Recording,
+// without touching the network. Requests are matched on method and
+// redacted URL; identical requests get their recorded responses in the
+// original order, and the last one repeats once they run out.
+type Replay struct {
+	mu        sync.Mutex
+	exchanges map[string][]RecordedResponse
+	served    map[string]int
+}
+
+// NewReplay loads every recording in dir.
+func NewReplay(dir string) (*Replay, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no recordings in %s", dir)
+	}
+	sort.Strings(files)
+
+	r := &Replay{exchanges: map[string][]RecordedResponse{}, served: map[string]int{}}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading recording: %w", err)
+		}
+		var ex Exchange
+		if err := json.Unmarshal(data, &ex); err != nil {
+			return nil, fmt.Errorf("parsing recording %s: %w", filepath.Base(f), err)
+		}
+		u, err := url.Parse(ex.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing recording %s: %w", filepath.Base(f), err)
+		}
+		k := ex.Request.Method + " " + RedactURL(u)
+		r.exchanges[k] = append(r.exchanges[k], ex.Response)
+	}
+	return r, nil
+}
+
+// Do returns the next recorded response for req.
+func (r *Replay) Do(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	k := req.Method + " " + RedactURL(req.URL)
+
+	r.mu.Lock()
+	responses := r.exchanges[k]
+	i := min(r.served[k], len(responses)-1)
+	r.served[k]++
+	r.mu.Unlock()
+
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("%w for %s", ErrNotRecorded, k)
+	}
+	rec := responses[i]
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Status, http.StatusText(rec.Status)),
+		StatusCode:    rec.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rec.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(rec.Body)),
+		ContentLength: int64(len(rec.Body)),
+		Request:       req,
+	}, nil
+}
+
+// RedactURL returns u with credential query parameters replaced, any
+// userinfo password masked, and the query sorted, so the same request
+// always yields the same string. Use it wherever a request URL is logged.
+func RedactURL(u *url.URL) string {
+	c := *u
+	q := c.Query()
+	for _, p := range secretParams {
+		if q.Has(p) {
+			q.Set(p, Redacted)
+		}
+	}
+	c.RawQuery = q.Encode()
+	return c.Redacted()
+}
+
+func redactHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	h = h.Clone()
+	for _, name := range secretHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, Redacted)
+		}
+	}
+	return h
+}
+
+// credentials collects the secret values carried by req, so they can be
+// scrubbed wherever a server echoes them back.
+func credentials(req *http.Request) []string {
+	var out []string
+	q := req.URL.Query()
+	for _, p := range secretParams {
+		if v := q.Get(p); v != "" {
+			out = append(out, v)
+		}
+	}
+	for _, name := range secretHeaders {
+		if v := req.Header.Get(name); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func redactString(s string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	return s
+}
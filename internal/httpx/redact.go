@@ -0,0 +1,48 @@
+package httpx
+
+import (
+	"net/http"
+)
+
+// Redacting is a Client that scrubs the credentials a request carries from
+// any error returned for it. Transport errors such as *url.Error quote the
+// full URL, so without this a key sent as a query parameter would reach
+// the terminal and logs.
+type Redacting struct {
+	next Client
+}
+
+// NewRedacting wraps next so its errors never contain credentials.
+func NewRedacting(next Client) *Redacting {
+	return &Redacting{next: next}
+}
+
+// Do sends req and redacts the error, if any.
+func (r *Redacting) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.next.Do(req)
+	if err != nil {
+		err = RedactError(err, credentials(req)...)
+	}
+	return resp, err
+}
+
+// RedactError returns err with every secret in its message replaced by
+// Redacted. The original stays reachable through errors.Is and errors.As.
+func RedactError(err error, secrets ...string) error {
+	if err == nil {
+		return nil
+	}
+	msg := redactString(err.Error(), secrets)
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
@@ -0,0 +1,45 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type failingClient struct{}
+
+func (failingClient) Do(req *http.Request) (*http.Response, error) {
+	return nil, &url.Error{Op: "Get", URL: req.URL.String(), Err: errors.New("dial tcp: connection refused " + req.Header.Get("X-Goog-Api-Key"))}
+}
+
+func TestRedacting(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://pollen.googleapis.com/v1/forecast:lookup?key="+testKey+"&days=1", nil)
+	req.Header.Set("X-Goog-Api-Key", testKey)
+
+	_, err := NewRedacting(failingClient{}).Do(req)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if strings.Contains(err.Error(), testKey) {
+		t.Errorf("error leaks the key: %v", err)
+	}
+	if !strings.Contains(err.Error(), "key="+Redacted) {
+		t.Errorf("error should keep its context: %v", err)
+	}
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Error("the original error should stay reachable")
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	if RedactError(nil, testKey) != nil {
+		t.Error("nil should stay nil")
+	}
+	plain := errors.New("timeout")
+	if RedactError(plain, testKey, "") != plain {
+		t.Error("errors without secrets should be returned as is")
+	}
+}
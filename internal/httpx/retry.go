@@ -0,0 +1,217 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shunito/pollenow/internal/logging"
+)
+
+// Client is a minimal interface satisfied by *http.Client. It matches the
+// HTTPClient interfaces of the geocoding and pollen packages, so decorators
+// here can wrap either client.
+type Client interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts, including the first
+	BaseDelay   time.Duration // backoff before the first retry
+	MaxDelay    time.Duration // cap on any single backoff wait
+	// MaxRetryAfter is the longest Retry-After the server may ask for.
+	// Longer requests are not shortened, since retrying early only earns
+	// another refusal; the response is returned instead. Zero means
+	// MaxDelay.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy makes up to four attempts over a few seconds, waiting
+// up to 20 seconds when the server asks for it with Retry-After.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   4,
+	BaseDelay:     250 * time.Millisecond,
+	MaxDelay:      5 * time.Second,
+	MaxRetryAfter: 20 * time.Second,
+}
+
+// Retrying is a Client that retries transport errors, 429s, and 5xx
+// responses with jittered exponential backoff. It honors Retry-After up to
+// the policy's MaxRetryAfter, never waits past the request's context
+// deadline, and only retries requests that are safe to repeat.
+type Retrying struct {
+	next   Client
+	policy RetryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+	log    *slog.Logger
+}
+
+// NewRetrying wraps next with policy. A nil next uses http.DefaultClient.
+func NewRetrying(next Client, policy RetryPolicy) *Retrying {
+	if next == nil {
+		next = http.DefaultClient
+	}
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &Retrying{next: next, policy: policy, sleep: sleepContext, log: logging.Discard}
+}
+
+// WithLogger sets the logger for retries. A nil l keeps the current one.
+func (r *Retrying) WithLogger(l *slog.Logger) *Retrying {
+	if l != nil {
+		r.log = l
+	}
+	return r
+}
+
+// Do sends req, retrying as the policy allows. The last response or error
+// is returned once attempts run out.
+func (r *Retrying) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attempts := r.policy.MaxAttempts
+	if !replayable(req) {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		try, err := rewind(req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := r.next.Do(try)
+		if attempt >= attempts || !retryable(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := r.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+				if after > r.maxRetryAfter() {
+					// The server wants more time than we will wait.
+					return resp, err
+				}
+				delay = after
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			// Waiting would outlive the caller; report what we have.
+			return resp, err
+		}
+
+		attrs := []any{"url", RedactURL(req.URL), "attempt", attempt, "delay", delay}
+		if resp != nil {
+			attrs = append(attrs, "status", resp.StatusCode)
+		} else {
+			attrs = append(attrs, "err", err)
+		}
+		r.log.Info("retrying request", attrs...)
+
+		if resp != nil {
+			// Drain so the connection can be reused.
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		if err := r.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// maxRetryAfter returns the longest Retry-After the policy honors.
+func (r *Retrying) maxRetryAfter() time.Duration {
+	if r.policy.MaxRetryAfter > 0 {
+		return r.policy.MaxRetryAfter
+	}
+	return r.policy.MaxDelay
+}
+
+// backoff returns a full-jitter delay for the given attempt: a random
+// duration up to BaseDelay * 2^(attempt-1), capped at MaxDelay.
+func (r *Retrying) backoff(attempt int) time.Duration {
+	ceiling := r.policy.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > r.policy.MaxDelay {
+		ceiling = r.policy.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+// replayable reports whether req may be sent more than once: its method
+// must be idempotent (or it must carry an Idempotency-Key), and its body
+// must be re-readable.
+func replayable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		if req.Header.Get("Idempotency-Key") == "" {
+			return false
+		}
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind returns a copy of req with a fresh body for another attempt.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	try := req.Clone(req.Context())
+	try.Body = body
+	return try, nil
+}
+
+// retryable reports whether the outcome of an attempt is worth retrying.
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		// A replay that has no answer will not grow one.
+		return !errors.Is(err, ErrNotRecorded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
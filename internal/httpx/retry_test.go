@@ -0,0 +1,215 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeServer answers with the given status codes in order, then 200.
+func fakeServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(statuses) {
+			if statuses[n-1] == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "2")
+			}
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(append([]byte("ok:"), body...))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+// newTestRetrying records waits instead of sleeping.
+func newTestRetrying(policy RetryPolicy) (*Retrying, *[]time.Duration) {
+	var waits []time.Duration
+	r := NewRetrying(http.DefaultClient, policy)
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	return r, &waits
+}
+
+func TestRetryRecovers(t *testing.T) {
+	srv, calls := fakeServer(t, 503, 500)
+	r, waits := newTestRetrying(DefaultRetryPolicy)
+	var logs bytes.Buffer
+	r.WithLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := r.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 200 || calls.Load() != 3 {
+		t.Errorf("got status %d after %d calls, want 200 after 3", resp.StatusCode, calls.Load())
+	}
+	if n := strings.Count(logs.String(), `msg="retrying request"`); n != 2 {
+		t.Errorf("logged %d retries, want 2:\n%s", n, logs.String())
+	}
+	for i, w := range *waits {
+		if ceiling := DefaultRetryPolicy.BaseDelay << i; w < 0 || w >= ceiling {
+			t.Errorf("wait %d: %v outside [0, %v)", i, w, ceiling)
+		}
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	srv, calls := fakeServer(t, 502, 502, 502, 502, 502)
+	r, _ := newTestRetrying(DefaultRetryPolicy)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := r.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 502 || int(calls.Load()) != DefaultRetryPolicy.MaxAttempts {
+		t.Errorf("got %d after %d calls, want the last 502 after %d", resp.StatusCode, calls.Load(), DefaultRetryPolicy.MaxAttempts)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	srv, _ := fakeServer(t, 429)
+	r, waits := newTestRetrying(DefaultRetryPolicy)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := r.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(*waits) != 1 || (*waits)[0] != 2*time.Second {
+		t.Errorf("waits: got %v, want [2s] from Retry-After", *waits)
+	}
+}
+
+func TestRetryAfterBeyondCap(t *testing.T) {
+	srv, calls := fakeServer(t, 429)
+
+	// Retry-After: 2 is longer than MaxDelay but within MaxRetryAfter.
+	r, waits := newTestRetrying(RetryPolicy{MaxAttempts: 3, MaxDelay: time.Second, MaxRetryAfter: 3 * time.Second})
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := r.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 || len(*waits) != 1 || (*waits)[0] != 2*time.Second {
+		t.Errorf("got %d after waits %v, want 200 after [2s]", resp.StatusCode, *waits)
+	}
+
+	// Past MaxRetryAfter the 429 is returned rather than retried early.
+	calls.Store(0)
+	r, waits = newTestRetrying(RetryPolicy{MaxAttempts: 3, MaxDelay: time.Second, MaxRetryAfter: time.Second})
+	resp, err = r.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 1 || len(*waits) != 0 {
+		t.Errorf("got %d after %d calls and waits %v, want the 429 after 1 call", resp.StatusCode, calls.Load(), *waits)
+	}
+}
+
+func TestRetryNoRetryOnClientError(t *testing.T) {
+	srv, calls := fakeServer(t, 403)
+	r, _ := newTestRetrying(DefaultRetryPolicy)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, _ := r.Do(req)
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Errorf("403 should not be retried: %d calls", calls.Load())
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	srv, calls := fakeServer(t, 429, 429)
+	r, waits := newTestRetrying(DefaultRetryPolicy)
+
+	// Retry-After asks for 2s but only 1s is left.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := r.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 429 || calls.Load() != 1 || len(*waits) != 0 {
+		t.Errorf("got %d after %d calls and waits %v, want the 429 without waiting", resp.StatusCode, calls.Load(), *waits)
+	}
+}
+
+func TestRetryIdempotency(t *testing.T) {
+	srv, calls := fakeServer(t, 503)
+	r, _ := newTestRetrying(DefaultRetryPolicy)
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("payload"))
+	resp, _ := r.Do(req)
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Errorf("POST should not be retried: %d calls", calls.Load())
+	}
+
+	srv, calls = fakeServer(t, 503)
+	req, _ = http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("payload"))
+	req.Header.Set("Idempotency-Key", "abc")
+	resp, err := r.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if calls.Load() != 2 || string(body) != "ok:payload" {
+		t.Errorf("POST with Idempotency-Key: %d calls, body %q; want 2 calls and the body resent", calls.Load(), body)
+	}
+}
+
+func TestRetryTransportError(t *testing.T) {
+	srv, _ := fakeServer(t)
+	url := srv.URL
+	srv.Close()
+
+	r, waits := newTestRetrying(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	if _, err := r.Do(req); err == nil {
+		t.Fatal("expected error from closed server")
+	}
+	if len(*waits) != 2 {
+		t.Errorf("got %d waits, want 2", len(*waits))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d, ok := retryAfter("7"); !ok || d != 7*time.Second {
+		t.Errorf("seconds: got %v, %v", d, ok)
+	}
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if d, ok := retryAfter(date); !ok || d < 58*time.Second || d > time.Minute {
+		t.Errorf("HTTP date: got %v, %v", d, ok)
+	}
+	if _, ok := retryAfter("soon"); ok {
+		t.Error("garbage should not parse")
+	}
+}
@@ -0,0 +1,61 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracing(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/forecast:lookup?key="+testKey, nil)
+	resp, err := NewTracing(http.DefaultClient).Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	s := spans[0]
+	if traceparent == "" || traceparent[3:35] != s.SpanContext.TraceID().String() {
+		t.Errorf("traceparent %q does not carry trace %s", traceparent, s.SpanContext.TraceID())
+	}
+	for _, a := range s.Attributes {
+		switch a.Key {
+		case "http.response.status_code":
+			if a.Value.AsInt64() != 429 {
+				t.Errorf("status attribute: %v", a.Value)
+			}
+		case "url.full":
+			if a.Value.AsString() != srv.URL+"/v1/forecast:lookup?key="+Redacted {
+				t.Errorf("url.full: %s", a.Value.AsString())
+			}
+		}
+	}
+	if req.Header.Get("traceparent") != "" {
+		t.Error("the caller's request should not be modified")
+	}
+}
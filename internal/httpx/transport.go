@@ -0,0 +1,101 @@
+package httpx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+)
+
+var (
+	ErrNoCertificates = errors.New("no certificates found in CA bundle")
+)
+
+// TransportOptions configure NewTransport.
+type TransportOptions struct {
+	Proxy    string // proxy URL; empty uses HTTP_PROXY, HTTPS_PROXY, and NO_PROXY
+	CABundle string // PEM file trusted in addition to the system roots
+}
+
+// NewTransport returns a transport with the same defaults as
+// http.DefaultTransport (connection pooling, HTTP/2, dial and TLS
+// handshake timeouts) plus opts.
+func NewTransport(opts TransportOptions) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		u, err := url.Parse(opts.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+
+	if opts.CABundle != "" {
+		pem, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w %s", ErrNoCertificates, opts.CABundle)
+		}
+		t.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return t, nil
+}
+
+// Transports hands out one shared transport per distinct set of options,
+// so clients with the same settings share a connection pool.
+type Transports struct {
+	mu sync.Mutex
+	m  map[TransportOptions]*http.Transport
+}
+
+// Get returns the transport for opts, creating it on first use.
+func (ts *Transports) Get(opts TransportOptions) (*http.Transport, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if t, ok := ts.m[opts]; ok {
+		return t, nil
+	}
+	t, err := NewTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+	if ts.m == nil {
+		ts.m = make(map[TransportOptions]*http.Transport)
+	}
+	ts.m[opts] = t
+	return t, nil
+}
+
+// UserAgent is a Client that sets the User-Agent header on every request
+// that does not already have one.
+type UserAgent struct {
+	next  Client
+	agent string
+}
+
+// NewUserAgent wraps next so requests carry agent as their User-Agent.
+func NewUserAgent(next Client, agent string) *UserAgent {
+	return &UserAgent{next: next, agent: agent}
+}
+
+// Do sets the header on a copy of req and sends it.
+func (u *UserAgent) Do(req *http.Request) (*http.Response, error) {
+	if u.agent == "" || req.Header.Get("User-Agent") != "" {
+		return u.next.Do(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", u.agent)
+	return u.next.Do(req)
+}
@@ -0,0 +1,101 @@
+package httpx
+
+import (
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransportProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String() // proxies receive the absolute URL
+	}))
+	defer proxy.Close()
+
+	tr, err := NewTransport(TransportOptions{Proxy: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	resp, err := (&http.Client{Transport: tr}).Get("http://pollen.example.com/v1/forecast:lookup")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if proxied != "http://pollen.example.com/v1/forecast:lookup" {
+		t.Errorf("proxy saw %q", proxied)
+	}
+}
+
+func TestTransportCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// Without the bundle, the test server's certificate is untrusted.
+	plain, _ := NewTransport(TransportOptions{})
+	if _, err := (&http.Client{Transport: plain}).Get(srv.URL); err == nil {
+		t.Fatal("expected a certificate error without the CA bundle")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}
+	if err := os.WriteFile(bundle, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := NewTransport(TransportOptions{CABundle: bundle})
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	resp, err := (&http.Client{Transport: tr}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request with CA bundle failed: %v", err)
+	}
+	resp.Body.Close()
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	os.WriteFile(empty, []byte("not a certificate"), 0o600)
+	if _, err := NewTransport(TransportOptions{CABundle: empty}); !errors.Is(err, ErrNoCertificates) {
+		t.Errorf("expected ErrNoCertificates, got %v", err)
+	}
+}
+
+func TestTransportsShared(t *testing.T) {
+	var ts Transports
+	a, _ := ts.Get(TransportOptions{})
+	b, _ := ts.Get(TransportOptions{})
+	c, _ := ts.Get(TransportOptions{Proxy: "http://proxy.internal:3128"})
+	if a != b {
+		t.Error("same options should share a transport")
+	}
+	if a == c {
+		t.Error("different options should not share a transport")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+	}))
+	defer srv.Close()
+
+	c := NewUserAgent(http.DefaultClient, "pollenow/1.2.3")
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if got != "pollenow/1.2.3" {
+		t.Errorf("User-Agent: got %q", got)
+	}
+	if req.Header.Get("User-Agent") != "" {
+		t.Error("the caller's request should not be modified")
+	}
+}
@@ -0,0 +1,492 @@
+package i18n
+
+// catalogs holds the CLI's own text by language. English is complete;
+// other catalogs may omit keys, which then fall back to English. Messages
+// are fmt format strings and must take the same arguments in every
+// language, though indexed verbs ("%[2]d") may reorder or skip them.
+var catalogs = map[string]map[string]string{
+	"en": {
+		"forecast.title":               "PolleNow - Pollen Forecast",
+		"forecast.cached":              "(cached %dm ago)",
+		"forecast.no_data":             "No forecast data available.",
+		"forecast.health":              "Health Recommendations",
+		"table.day":                    "Day",
+		"table.vs_typical":             "vs. Typical",
+		"type.grass":                   "Grass",
+		"type.tree":                    "Tree",
+		"type.weed":                    "Weed",
+		"level.no_data":                "No Data",
+		"level.na":                     "N/A",
+		"legend.in_season":             "* = in season",
+		"legend.norms":                 "↑ above / ≈ typical / ↓ below the norm for this week, from your history",
+		"norm.above":                   "above typical",
+		"norm.typical":                 "typical",
+		"norm.below":                   "below typical",
+		"summary.alert":                "%s pollen is %s today%s — consider limiting outdoor activity",
+		"summary.norm":                 " (%s for this week)",
+		"summary.unusual":              "%s: %s (%s for this week)",
+		"summary.all_low":              "✓ All pollen levels are low today",
+		"day.yesterday":                "Yesterday",
+		"day.today":                    "Today",
+		"day.tomorrow":                 "Tomorrow",
+		"day.sunday":                   "Sunday",
+		"day.monday":                   "Monday",
+		"day.tuesday":                  "Tuesday",
+		"day.wednesday":                "Wednesday",
+		"day.thursday":                 "Thursday",
+		"day.friday":                   "Friday",
+		"day.saturday":                 "Saturday",
+		"error.prefix":                 "Error: %s",
+		"error.no_zip":                 "no ZIP code provided\nUsage: %s [ZIP]\nOr set a default: pollenow config set default_zip 94025",
+		"error.invalid_zip":            "invalid ZIP code %q — please enter a 5-digit US ZIP code",
+		"error.days":                   "days must be between 1 and 5",
+		"error.key_hint":               "Run: pollenow config set api_key YOUR_KEY\nOr reference a secret: pollenow config set api_key_command \"pass show google/pollen\"\nOr set POLLENOW_API_KEY environment variable",
+		"map.title":                    "PolleNow - %s Pollen Map",
+		"map.location":                 "%s · zoom %d",
+		"map.legend":                   "Color shows the Universal Pollen Index, green (low) to red (very high). The cross marks the location.",
+		"map.saved":                    "  ✓ Saved %s",
+		"map.no_location":              "no location provided\nUsage: pollenow map [ZIP | LAT,LNG]\nOr set a default: pollenow config set default_zip 94025",
+		"map.bad_location":             "invalid location %q — enter a 5-digit US ZIP code or LAT,LNG",
+		"month.january":                "January",
+		"month.february":               "February",
+		"month.march":                  "March",
+		"month.april":                  "April",
+		"month.may":                    "May",
+		"month.june":                   "June",
+		"month.july":                   "July",
+		"month.august":                 "August",
+		"month.september":              "September",
+		"month.october":                "October",
+		"month.november":               "November",
+		"month.december":               "December",
+		"date.short":                   "%.3[3]s %02[2]d",
+		"plan.title":                   "PolleNow - Medication Plan",
+		"plan.none":                    "No medication needed — none of your targets are high in the forecast",
+		"plan.taken":                   "%s taken for %s",
+		"plan.take_by":                 "  Take by %.3s %s, then: pollenow plan --taken %s",
+		"plan.advice":                  "Take %s %s — %s %s",
+		"plan.trigger":                 "%s is %s",
+		"plan.when.now":                "now",
+		"plan.when.this_morning":       "this morning",
+		"plan.when.this_afternoon":     "this afternoon",
+		"plan.when.tonight":            "tonight",
+		"plan.when.tomorrow_morning":   "tomorrow morning",
+		"plan.when.tomorrow_afternoon": "tomorrow afternoon",
+		"plan.when.tomorrow_night":     "tomorrow night",
+		"plan.when.day_morning":        "%s morning",
+		"plan.when.day_afternoon":      "%s afternoon",
+		"plan.when.day_night":          "%s night",
+		"plan.on.today":                "today",
+		"plan.on.tomorrow":             "tomorrow",
+		"plan.on.day":                  "on %s",
+		"plan.on.unknown":              "that day",
+		"doctor.title":                 "PolleNow - Doctor",
+		"doctor.totals":                "  %d passed, %d warnings, %d failed",
+		"season.title":                 "PolleNow - Pollen Seasons",
+		"season.none":                  "No season history yet — forecasts are recorded each time you run pollenow.",
+		"season.months":                "JFMAMJJASOND",
+		"season.legend":                "%s = in season   %s = this week",
+		"season.range":                 "%s – %s (%d days)",
+		"season.ongoing":               "ongoing",
+		"season.peak":                  ", peak %d on %s",
+		"usage.title":                  "PolleNow - API Usage",
+		"usage.month":                  "%s %d",
+		"usage.none":                   "No API requests recorded this month.",
+		"usage.api":                    "API",
+		"usage.requests":               "Requests",
+		"usage.failed":                 "Failed",
+		"usage.latency":                "Avg latency",
+		"usage.cost":                   "Est. cost",
+		"usage.total":                  "total",
+		"usage.cost_note":              "Cost at list price, before Google's free monthly credit",
+		"usage.today":                  "Today: %d requests",
+		"usage.of":                     " of %d",
+		"usage.soft":                   "%s — over the soft cap of %d",
+		"usage.hard":                   "%s — hard cap of %d reached, API requests are refused",
+	},
+	"es": {
+		"forecast.title":               "PolleNow - Pronóstico de polen",
+		"forecast.cached":              "(en caché hace %dm)",
+		"forecast.no_data":             "No hay datos de pronóstico disponibles.",
+		"forecast.health":              "Recomendaciones de salud",
+		"table.day":                    "Día",
+		"table.vs_typical":             "vs. habitual",
+		"type.grass":                   "Gramíneas",
+		"type.tree":                    "Árboles",
+		"type.weed":                    "Malezas",
+		"level.no_data":                "Sin datos",
+		"level.na":                     "N/D",
+		"legend.in_season":             "* = en temporada",
+		"legend.norms":                 "↑ por encima / ≈ habitual / ↓ por debajo de lo normal para esta semana, según tu historial",
+		"norm.above":                   "por encima de lo habitual",
+		"norm.typical":                 "habitual",
+		"norm.below":                   "por debajo de lo habitual",
+		"summary.alert":                "El polen de %s está %s hoy%s — considera limitar las actividades al aire libre",
+		"summary.norm":                 " (%s para esta semana)",
+		"summary.unusual":              "%s: %s (%s para esta semana)",
+		"summary.all_low":              "✓ Todos los niveles de polen están bajos hoy",
+		"day.yesterday":                "Ayer",
+		"day.today":                    "Hoy",
+		"day.tomorrow":                 "Mañana",
+		"day.sunday":                   "Domingo",
+		"day.monday":                   "Lunes",
+		"day.tuesday":                  "Martes",
+		"day.wednesday":                "Miércoles",
+		"day.thursday":                 "Jueves",
+		"day.friday":                   "Viernes",
+		"day.saturday":                 "Sábado",
+		"error.prefix":                 "Error: %s",
+		"error.no_zip":                 "no se indicó un código postal\nUso: %s [ZIP]\nO define uno por defecto: pollenow config set default_zip 94025",
+		"error.invalid_zip":            "código postal %q no válido — introduce un código postal de EE. UU. de 5 dígitos",
+		"error.days":                   "los días deben estar entre 1 y 5",
+		"error.key_hint":               "Ejecuta: pollenow config set api_key TU_CLAVE\nO haz referencia a un secreto: pollenow config set api_key_command \"pass show google/pollen\"\nO define la variable de entorno POLLENOW_API_KEY",
+		"map.title":                    "PolleNow - Mapa de polen de %s",
+		"map.location":                 "%s · zoom %d",
+		"map.legend":                   "El color muestra el Índice Universal de Polen, de verde (bajo) a rojo (muy alto). La cruz marca la ubicación.",
+		"map.saved":                    "  ✓ Guardado %s",
+		"map.no_location":              "no se indicó una ubicación\nUso: pollenow map [ZIP | LAT,LNG]\nO define uno por defecto: pollenow config set default_zip 94025",
+		"map.bad_location":             "ubicación %q no válida — introduce un código postal de EE. UU. de 5 dígitos o LAT,LNG",
+		"month.january":                "enero",
+		"month.february":               "febrero",
+		"month.march":                  "marzo",
+		"month.april":                  "abril",
+		"month.may":                    "mayo",
+		"month.june":                   "junio",
+		"month.july":                   "julio",
+		"month.august":                 "agosto",
+		"month.september":              "septiembre",
+		"month.october":                "octubre",
+		"month.november":               "noviembre",
+		"month.december":               "diciembre",
+		"date.short":                   "%02[2]d/%02[1]d",
+		"plan.title":                   "PolleNow - Plan de medicación",
+		"plan.none":                    "No hace falta medicación — ninguno de tus objetivos está alto en el pronóstico",
+		"plan.taken":                   "%s tomado para el %s",
+		"plan.take_by":                 "  Tómalo antes del %s a las %s, luego: pollenow plan --taken %s",
+		"plan.advice":                  "Toma %s %s — %s %s",
+		"plan.trigger":                 "%s está %s",
+		"plan.when.now":                "ahora",
+		"plan.when.this_morning":       "esta mañana",
+		"plan.when.this_afternoon":     "esta tarde",
+		"plan.when.tonight":            "esta noche",
+		"plan.when.tomorrow_morning":   "mañana por la mañana",
+		"plan.when.tomorrow_afternoon": "mañana por la tarde",
+		"plan.when.tomorrow_night":     "mañana por la noche",
+		"plan.when.day_morning":        "el %s por la mañana",
+		"plan.when.day_afternoon":      "el %s por la tarde",
+		"plan.when.day_night":          "el %s por la noche",
+		"plan.on.today":                "hoy",
+		"plan.on.tomorrow":             "mañana",
+		"plan.on.day":                  "el %s",
+		"plan.on.unknown":              "ese día",
+		"doctor.title":                 "PolleNow - Diagnóstico",
+		"doctor.totals":                "  %d correctas, %d avisos, %d fallidas",
+		"season.title":                 "PolleNow - Temporadas de polen",
+		"season.none":                  "Aún no hay historial de temporadas — los pronósticos se guardan cada vez que ejecutas pollenow.",
+		"season.months":                "EFMAMJJASOND",
+		"season.legend":                "%s = en temporada   %s = esta semana",
+		"season.range":                 "%s – %s (%d días)",
+		"season.ongoing":               "en curso",
+		"season.peak":                  ", pico %d el %s",
+		"usage.title":                  "PolleNow - Uso de la API",
+		"usage.month":                  "%s de %d",
+		"usage.none":                   "No hay solicitudes a la API registradas este mes.",
+		"usage.requests":               "Solicitudes",
+		"usage.failed":                 "Fallidas",
+		"usage.latency":                "Latencia media",
+		"usage.cost":                   "Coste est.",
+		"usage.cost_note":              "Coste a precio de lista, antes del crédito mensual gratuito de Google",
+		"usage.today":                  "Hoy: %d solicitudes",
+		"usage.of":                     " de %d",
+		"usage.soft":                   "%s — por encima del límite blando de %d",
+		"usage.hard":                   "%s — alcanzado el límite estricto de %d, se rechazan las solicitudes a la API",
+	},
+	"fr": {
+		"forecast.title":               "PolleNow - Prévisions polliniques",
+		"forecast.cached":              "(en cache depuis %d min)",
+		"forecast.no_data":             "Aucune prévision disponible.",
+		"forecast.health":              "Recommandations santé",
+		"table.day":                    "Jour",
+		"table.vs_typical":             "vs. habituel",
+		"type.grass":                   "Graminées",
+		"type.tree":                    "Arbres",
+		"type.weed":                    "Herbacées",
+		"level.no_data":                "Aucune donnée",
+		"level.na":                     "N/D",
+		"legend.in_season":             "* = en saison",
+		"legend.norms":                 "↑ au-dessus / ≈ habituel / ↓ en dessous de la normale de cette semaine, d'après votre historique",
+		"norm.above":                   "au-dessus de l'habituel",
+		"norm.typical":                 "habituel",
+		"norm.below":                   "en dessous de l'habituel",
+		"summary.alert":                "Pollen (%s) : niveau %s aujourd'hui%s — limitez les activités en extérieur",
+		"summary.norm":                 " (%s pour cette semaine)",
+		"summary.unusual":              "%s : %s (%s pour cette semaine)",
+		"summary.all_low":              "✓ Tous les niveaux de pollen sont bas aujourd'hui",
+		"day.yesterday":                "Hier",
+		"day.today":                    "Aujourd'hui",
+		"day.tomorrow":                 "Demain",
+		"day.sunday":                   "Dimanche",
+		"day.monday":                   "Lundi",
+		"day.tuesday":                  "Mardi",
+		"day.wednesday":                "Mercredi",
+		"day.thursday":                 "Jeudi",
+		"day.friday":                   "Vendredi",
+		"day.saturday":                 "Samedi",
+		"error.prefix":                 "Erreur : %s",
+		"error.no_zip":                 "aucun code postal fourni\nUsage : %s [ZIP]\nOu définissez-en un par défaut : pollenow config set default_zip 94025",
+		"error.invalid_zip":            "code postal %q invalide — saisissez un code postal américain à 5 chiffres",
+		"error.days":                   "le nombre de jours doit être compris entre 1 et 5",
+		"error.key_hint":               "Exécutez : pollenow config set api_key VOTRE_CLÉ\nOu référencez un secret : pollenow config set api_key_command \"pass show google/pollen\"\nOu définissez la variable d'environnement POLLENOW_API_KEY",
+		"map.title":                    "PolleNow - Carte du pollen (%s)",
+		"map.location":                 "%s · zoom %d",
+		"map.legend":                   "La couleur indique l'indice pollinique universel, du vert (faible) au rouge (très élevé). La croix marque le lieu.",
+		"map.saved":                    "  ✓ Enregistré : %s",
+		"map.no_location":              "aucun lieu fourni\nUsage : pollenow map [ZIP | LAT,LNG]\nOu définissez-en un par défaut : pollenow config set default_zip 94025",
+		"map.bad_location":             "lieu %q invalide — saisissez un code postal américain à 5 chiffres ou LAT,LNG",
+		"month.january":                "janvier",
+		"month.february":               "février",
+		"month.march":                  "mars",
+		"month.april":                  "avril",
+		"month.may":                    "mai",
+		"month.june":                   "juin",
+		"month.july":                   "juillet",
+		"month.august":                 "août",
+		"month.september":              "septembre",
+		"month.october":                "octobre",
+		"month.november":               "novembre",
+		"month.december":               "décembre",
+		"date.short":                   "%02[2]d/%02[1]d",
+		"plan.title":                   "PolleNow - Plan de traitement",
+		"plan.none":                    "Aucun médicament nécessaire — aucune de vos cibles n'est élevée dans les prévisions",
+		"plan.taken":                   "%s pris pour le %s",
+		"plan.take_by":                 "  À prendre avant %s %s, puis : pollenow plan --taken %s",
+		"plan.advice":                  "Prenez %s %s — %s %s",
+		"plan.trigger":                 "%s : %s",
+		"plan.when.now":                "maintenant",
+		"plan.when.this_morning":       "ce matin",
+		"plan.when.this_afternoon":     "cet après-midi",
+		"plan.when.tonight":            "ce soir",
+		"plan.when.tomorrow_morning":   "demain matin",
+		"plan.when.tomorrow_afternoon": "demain après-midi",
+		"plan.when.tomorrow_night":     "demain soir",
+		"plan.when.day_morning":        "%s matin",
+		"plan.when.day_afternoon":      "%s après-midi",
+		"plan.when.day_night":          "%s soir",
+		"plan.on.today":                "aujourd'hui",
+		"plan.on.tomorrow":             "demain",
+		"plan.on.day":                  "%s",
+		"plan.on.unknown":              "ce jour-là",
+		"doctor.title":                 "PolleNow - Diagnostic",
+		"doctor.totals":                "  %d réussis, %d avertissements, %d échecs",
+		"season.title":                 "PolleNow - Saisons polliniques",
+		"season.none":                  "Pas encore d'historique des saisons — les prévisions sont enregistrées à chaque exécution de pollenow.",
+		"season.legend":                "%s = en saison   %s = cette semaine",
+		"season.range":                 "%s – %s (%d jours)",
+		"season.ongoing":               "en cours",
+		"season.peak":                  ", pic %d le %s",
+		"usage.title":                  "PolleNow - Utilisation de l'API",
+		"usage.none":                   "Aucune requête API enregistrée ce mois-ci.",
+		"usage.requests":               "Requêtes",
+		"usage.failed":                 "Échecs",
+		"usage.latency":                "Latence moy.",
+		"usage.cost":                   "Coût est.",
+		"usage.cost_note":              "Coût au prix catalogue, avant le crédit mensuel gratuit de Google",
+		"usage.today":                  "Aujourd'hui : %d requêtes",
+		"usage.of":                     " sur %d",
+		"usage.soft":                   "%s — au-delà du plafond souple de %d",
+		"usage.hard":                   "%s — plafond strict de %d atteint, les requêtes API sont refusées",
+	},
+	"de": {
+		"forecast.title":               "PolleNow - Pollenvorhersage",
+		"forecast.cached":              "(vor %d Min. zwischengespeichert)",
+		"forecast.no_data":             "Keine Vorhersagedaten verfügbar.",
+		"forecast.health":              "Gesundheitsempfehlungen",
+		"table.day":                    "Tag",
+		"table.vs_typical":             "vs. üblich",
+		"type.grass":                   "Gräser",
+		"type.tree":                    "Bäume",
+		"type.weed":                    "Kräuter",
+		"level.no_data":                "Keine Daten",
+		"level.na":                     "k. A.",
+		"legend.in_season":             "* = in der Saison",
+		"legend.norms":                 "↑ über / ≈ üblich / ↓ unter dem Normalwert dieser Woche, aus Ihrem Verlauf",
+		"norm.above":                   "über dem Üblichen",
+		"norm.typical":                 "üblich",
+		"norm.below":                   "unter dem Üblichen",
+		"summary.alert":                "Pollen (%s) heute %s%s — Aktivitäten im Freien möglichst einschränken",
+		"summary.norm":                 " (%s für diese Woche)",
+		"summary.unusual":              "%s: %s (%s für diese Woche)",
+		"summary.all_low":              "✓ Alle Pollenwerte sind heute niedrig",
+		"day.yesterday":                "Gestern",
+		"day.today":                    "Heute",
+		"day.tomorrow":                 "Morgen",
+		"day.sunday":                   "Sonntag",
+		"day.monday":                   "Montag",
+		"day.tuesday":                  "Dienstag",
+		"day.wednesday":                "Mittwoch",
+		"day.thursday":                 "Donnerstag",
+		"day.friday":                   "Freitag",
+		"day.saturday":                 "Samstag",
+		"error.prefix":                 "Fehler: %s",
+		"error.no_zip":                 "keine Postleitzahl angegeben\nAufruf: %s [ZIP]\nOder Standard festlegen: pollenow config set default_zip 94025",
+		"error.invalid_zip":            "ungültige Postleitzahl %q — bitte eine 5-stellige US-Postleitzahl eingeben",
+		"error.days":                   "Tage müssen zwischen 1 und 5 liegen",
+		"error.key_hint":               "Ausführen: pollenow config set api_key IHR_SCHLÜSSEL\nOder auf ein Geheimnis verweisen: pollenow config set api_key_command \"pass show google/pollen\"\nOder die Umgebungsvariable POLLENOW_API_KEY setzen",
+		"map.title":                    "PolleNow - Pollenkarte (%s)",
+		"map.location":                 "%s · Zoom %d",
+		"map.legend":                   "Die Farbe zeigt den Universal Pollen Index, von Grün (niedrig) bis Rot (sehr hoch). Das Kreuz markiert den Ort.",
+		"map.saved":                    "  ✓ Gespeichert: %s",
+		"map.no_location":              "kein Ort angegeben\nAufruf: pollenow map [ZIP | LAT,LNG]\nOder Standard festlegen: pollenow config set default_zip 94025",
+		"map.bad_location":             "ungültiger Ort %q — bitte eine 5-stellige US-Postleitzahl oder LAT,LNG eingeben",
+		"month.january":                "Januar",
+		"month.february":               "Februar",
+		"month.march":                  "März",
+		"month.april":                  "April",
+		"month.may":                    "Mai",
+		"month.june":                   "Juni",
+		"month.july":                   "Juli",
+		"month.august":                 "August",
+		"month.september":              "September",
+		"month.october":                "Oktober",
+		"month.november":               "November",
+		"month.december":               "Dezember",
+		"date.short":                   "%02[2]d.%02[1]d.",
+		"plan.title":                   "PolleNow - Medikamentenplan",
+		"plan.none":                    "Keine Medikamente nötig — keines Ihrer Ziele ist in der Vorhersage hoch",
+		"plan.taken":                   "%s für %s eingenommen",
+		"plan.take_by":                 "  Einnehmen bis %s %s, dann: pollenow plan --taken %s",
+		"plan.advice":                  "%s %s einnehmen — %s %s",
+		"plan.trigger":                 "%s: %s",
+		"plan.when.now":                "jetzt",
+		"plan.when.this_morning":       "heute Morgen",
+		"plan.when.this_afternoon":     "heute Nachmittag",
+		"plan.when.tonight":            "heute Abend",
+		"plan.when.tomorrow_morning":   "morgen früh",
+		"plan.when.tomorrow_afternoon": "morgen Nachmittag",
+		"plan.when.tomorrow_night":     "morgen Abend",
+		"plan.when.day_morning":        "%smorgen",
+		"plan.when.day_afternoon":      "%snachmittag",
+		"plan.when.day_night":          "%sabend",
+		"plan.on.today":                "heute",
+		"plan.on.tomorrow":             "morgen",
+		"plan.on.day":                  "am %s",
+		"plan.on.unknown":              "an diesem Tag",
+		"doctor.title":                 "PolleNow - Diagnose",
+		"doctor.totals":                "  %d bestanden, %d Warnungen, %d fehlgeschlagen",
+		"season.title":                 "PolleNow - Pollensaisons",
+		"season.none":                  "Noch kein Saisonverlauf — Vorhersagen werden bei jedem Aufruf von pollenow gespeichert.",
+		"season.legend":                "%s = in der Saison   %s = diese Woche",
+		"season.range":                 "%s – %s (%d Tage)",
+		"season.ongoing":               "laufend",
+		"season.peak":                  ", Höchstwert %d am %s",
+		"usage.title":                  "PolleNow - API-Nutzung",
+		"usage.none":                   "Diesen Monat wurden keine API-Anfragen aufgezeichnet.",
+		"usage.requests":               "Anfragen",
+		"usage.failed":                 "Fehlgeschlagen",
+		"usage.latency":                "Ø Latenz",
+		"usage.cost":                   "Gesch. Kosten",
+		"usage.total":                  "gesamt",
+		"usage.cost_note":              "Kosten zum Listenpreis, vor Googles monatlichem Gratisguthaben",
+		"usage.today":                  "Heute: %d Anfragen",
+		"usage.of":                     " von %d",
+		"usage.soft":                   "%s — über dem weichen Limit von %d",
+		"usage.hard":                   "%s — hartes Limit von %d erreicht, API-Anfragen werden abgelehnt",
+	},
+	"ja": {
+		"forecast.title":               "PolleNow - 花粉予報",
+		"forecast.cached":              "(%d分前のキャッシュ)",
+		"forecast.no_data":             "予報データがありません。",
+		"forecast.health":              "健康アドバイス",
+		"table.day":                    "日",
+		"table.vs_typical":             "平年比",
+		"type.grass":                   "イネ科",
+		"type.tree":                    "樹木",
+		"type.weed":                    "雑草",
+		"level.no_data":                "データなし",
+		"level.na":                     "なし",
+		"legend.in_season":             "* = 飛散シーズン中",
+		"legend.norms":                 "↑ 多い / ≈ 平年並み / ↓ 少ない(履歴から算出した今週の平年値との比較)",
+		"norm.above":                   "平年より多い",
+		"norm.typical":                 "平年並み",
+		"norm.below":                   "平年より少ない",
+		"summary.alert":                "今日の%s花粉は「%s」です%s — 屋外での活動は控えめに",
+		"summary.norm":                 "(今週としては%s)",
+		"summary.unusual":              "%s: %s(今週としては%s)",
+		"summary.all_low":              "✓ 今日の花粉はすべて少なめです",
+		"day.yesterday":                "昨日",
+		"day.today":                    "今日",
+		"day.tomorrow":                 "明日",
+		"day.sunday":                   "日曜日",
+		"day.monday":                   "月曜日",
+		"day.tuesday":                  "火曜日",
+		"day.wednesday":                "水曜日",
+		"day.thursday":                 "木曜日",
+		"day.friday":                   "金曜日",
+		"day.saturday":                 "土曜日",
+		"error.prefix":                 "エラー: %s",
+		"error.no_zip":                 "ZIPコードが指定されていません\n使い方: %s [ZIP]\nデフォルトを設定: pollenow config set default_zip 94025",
+		"error.invalid_zip":            "ZIPコード %q が正しくありません — 5桁の米国ZIPコードを入力してください",
+		"error.days":                   "日数は1〜5で指定してください",
+		"error.key_hint":               "実行: pollenow config set api_key YOUR_KEY\nまたはシークレットを参照: pollenow config set api_key_command \"pass show google/pollen\"\nまたは環境変数 POLLENOW_API_KEY を設定",
+		"map.title":                    "PolleNow - %s花粉マップ",
+		"map.location":                 "%s · ズーム %d",
+		"map.legend":                   "色はユニバーサル花粉指数を表します(緑: 少ない 〜 赤: 非常に多い)。十字が指定地点です。",
+		"map.saved":                    "  ✓ %s に保存しました",
+		"map.no_location":              "場所が指定されていません\n使い方: pollenow map [ZIP | LAT,LNG]\nデフォルトを設定: pollenow config set default_zip 94025",
+		"map.bad_location":             "場所 %q が正しくありません — 5桁の米国ZIPコードか LAT,LNG を入力してください",
+		"month.january":                "1月",
+		"month.february":               "2月",
+		"month.march":                  "3月",
+		"month.april":                  "4月",
+		"month.may":                    "5月",
+		"month.june":                   "6月",
+		"month.july":                   "7月",
+		"month.august":                 "8月",
+		"month.september":              "9月",
+		"month.october":                "10月",
+		"month.november":               "11月",
+		"month.december":               "12月",
+		"date.short":                   "%[1]d月%[2]d日",
+		"plan.title":                   "PolleNow - 服薬プラン",
+		"plan.none":                    "服薬は不要です — 予報で高くなる対象はありません",
+		"plan.taken":                   "%s は %s の分を服用済み",
+		"plan.take_by":                 "  %s %s までに服用し、その後: pollenow plan --taken %s",
+		"plan.advice":                  "%sを%s服用 — %s%s",
+		"plan.trigger":                 "%sが%s",
+		"plan.when.now":                "今すぐ",
+		"plan.when.this_morning":       "今朝",
+		"plan.when.this_afternoon":     "今日の午後",
+		"plan.when.tonight":            "今夜",
+		"plan.when.tomorrow_morning":   "明日の朝",
+		"plan.when.tomorrow_afternoon": "明日の午後",
+		"plan.when.tomorrow_night":     "明日の夜",
+		"plan.when.day_morning":        "%sの朝",
+		"plan.when.day_afternoon":      "%sの午後",
+		"plan.when.day_night":          "%sの夜",
+		"plan.on.today":                "今日は",
+		"plan.on.tomorrow":             "明日は",
+		"plan.on.day":                  "%sは",
+		"plan.on.unknown":              "その日は",
+		"doctor.title":                 "PolleNow - 診断",
+		"doctor.totals":                "  成功 %d、警告 %d、失敗 %d",
+		"season.title":                 "PolleNow - 花粉シーズン",
+		"season.none":                  "シーズンの履歴はまだありません — 予報は pollenow を実行するたびに記録されます。",
+		"season.legend":                "%s = シーズン中   %s = 今週",
+		"season.range":                 "%s – %s (%d日間)",
+		"season.ongoing":               "継続中",
+		"season.peak":                  "、ピーク %d (%s)",
+		"usage.title":                  "PolleNow - API 使用状況",
+		"usage.month":                  "%[2]d年%[1]s",
+		"usage.none":                   "今月の API リクエストの記録はありません。",
+		"usage.requests":               "リクエスト",
+		"usage.failed":                 "失敗",
+		"usage.latency":                "平均レイテンシ",
+		"usage.cost":                   "推定費用",
+		"usage.total":                  "合計",
+		"usage.cost_note":              "Google の毎月の無料クレジット適用前の定価での費用",
+		"usage.today":                  "今日: %d リクエスト",
+		"usage.of":                     " / %d",
+		"usage.soft":                   "%s — ソフト上限 %d を超えています",
+		"usage.hard":                   "%s — ハード上限 %d に達したため、API リクエストは拒否されます",
+	},
+}
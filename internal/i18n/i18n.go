@@ -0,0 +1,110 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Default is the language used when none is configured, and the fallback
+// for messages a catalog lacks.
+const Default = "en"
+
+// tagRegex matches BCP 47 language tags as the Google APIs accept them:
+// a 2-3 letter language with optional script, region, or variant subtags,
+// e.g. "ja", "pt-BR", "zh-Hant-TW".
+var tagRegex = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// Valid reports whether tag is a well-formed language tag. Any valid tag
+// can be sent to the APIs; CLI text falls back to English for languages
+// without a catalog.
+func Valid(tag string) bool {
+	return tagRegex.MatchString(tag)
+}
+
+// Supported lists the languages with a catalog for CLI text, sorted.
+func Supported() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Printer formats CLI messages in one language.
+type Printer struct {
+	lang string
+	msgs map[string]string
+}
+
+// English prints the default catalog.
+var English = New(Default)
+
+// New returns a Printer for tag, using the catalog for the full tag, then
+// for its language alone ("pt-BR", then "pt"), then English.
+func New(tag string) *Printer {
+	tag = strings.ToLower(tag)
+	for _, t := range []string{tag, strings.SplitN(tag, "-", 2)[0]} {
+		if msgs, ok := catalogs[t]; ok {
+			return &Printer{lang: t, msgs: msgs}
+		}
+	}
+	return &Printer{lang: Default, msgs: catalogs[Default]}
+}
+
+// Lang returns the catalog language in use.
+func (p *Printer) Lang() string {
+	return p.lang
+}
+
+// T formats the message for key with args, as fmt.Sprintf does. Messages
+// missing from the catalog fall back to English, then to the key itself.
+func (p *Printer) T(key string, args ...any) string {
+	msg, ok := p.msgs[key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Weekday returns the name of d.
+func (p *Printer) Weekday(d time.Weekday) string {
+	return p.T("day." + strings.ToLower(d.String()))
+}
+
+// DayName labels date relative to today: "Yesterday", "Today", "Tomorrow",
+// or the weekday. Only the calendar dates count, so pass today as seen in
+// the forecast location's time zone.
+func (p *Printer) DayName(date, today time.Time) string {
+	y1, m1, d1 := today.Date()
+	y2, m2, d2 := date.Date()
+	days := int(time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).Sub(time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+	switch days {
+	case -1:
+		return p.T("day.yesterday")
+	case 0:
+		return p.T("day.today")
+	case 1:
+		return p.T("day.tomorrow")
+	default:
+		return p.Weekday(date.Weekday())
+	}
+}
+
+// Month returns the name of m.
+func (p *Printer) Month(m time.Month) string {
+	return p.T("month." + strings.ToLower(m.String()))
+}
+
+// ShortDate formats the month and day of t, e.g. "Jun 05" in English.
+func (p *Printer) ShortDate(t time.Time) string {
+	return p.T("date.short", int(t.Month()), t.Day(), p.Month(t.Month()))
+}
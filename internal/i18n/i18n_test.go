@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+// verbRegex matches fmt verbs, ignoring the %% escape.
+var verbRegex = regexp.MustCompile(`%[-+# 0-9.\[\]]*[a-zA-Z]`)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	en := catalogs[Default]
+	for lang, msgs := range catalogs {
+		for key, msg := range msgs {
+			want, ok := en[key]
+			if !ok {
+				t.Errorf("%s: key %q is not in the English catalog", lang, key)
+				continue
+			}
+			if got, want := verbRegex.FindAllString(msg, -1), verbRegex.FindAllString(want, -1); len(got) != len(want) {
+				t.Errorf("%s: %q has verbs %v, English has %v", lang, key, got, want)
+			}
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct{ tag, want string }{
+		{"ja", "ja"},
+		{"ja-JP", "ja"},
+		{"DE", "de"},
+		{"pt-BR", "en"},
+		{"", "en"},
+	}
+	for _, tt := range tests {
+		if got := New(tt.tag).Lang(); got != tt.want {
+			t.Errorf("New(%q).Lang() = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	p := New("ja")
+	if got := p.T("error.days"); got != "日数は1〜5で指定してください" {
+		t.Errorf("got %q", got)
+	}
+	if got := p.T("forecast.cached", 5); got != "(5分前のキャッシュ)" {
+		t.Errorf("got %q", got)
+	}
+	if got := p.T("no.such.key"); got != "no.such.key" {
+		t.Errorf("missing key: got %q", got)
+	}
+
+	// Keys missing from a catalog fall back to English.
+	p = &Printer{lang: "xx", msgs: map[string]string{}}
+	if got := p.T("day.today"); got != "Today" {
+		t.Errorf("fallback: got %q", got)
+	}
+}
+
+func TestDayName(t *testing.T) {
+	// Monday evening in Los Angeles, already Tuesday in UTC.
+	la, _ := time.LoadLocation("America/Los_Angeles")
+	today := time.Date(2025, 6, 16, 20, 0, 0, 0, la)
+	tests := []struct {
+		lang string
+		day  int // of June 2025
+		want string
+	}{
+		{"en", 15, "Yesterday"},
+		{"en", 16, "Today"},
+		{"en", 17, "Tomorrow"},
+		{"en", 18, "Wednesday"},
+		{"es", 18, "Miércoles"},
+		{"ja", 16, "今日"},
+		{"ja", 18, "水曜日"},
+	}
+	for _, tt := range tests {
+		date := time.Date(2025, 6, tt.day, 0, 0, 0, 0, time.UTC)
+		if got := New(tt.lang).DayName(date, today); got != tt.want {
+			t.Errorf("%s DayName(June %d) = %q, want %q", tt.lang, tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestShortDate(t *testing.T) {
+	date := time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)
+	tests := []struct{ lang, want string }{
+		{"en", "Jun 05"},
+		{"de", "05.06."},
+		{"ja", "6月5日"},
+	}
+	for _, tt := range tests {
+		if got := New(tt.lang).ShortDate(date); got != tt.want {
+			t.Errorf("%s ShortDate = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, tag := range []string{"en", "ja", "pt-BR", "zh-Hant-TW", "es-419"} {
+		if !Valid(tag) {
+			t.Errorf("Valid(%q) = false", tag)
+		}
+	}
+	for _, tag := range []string{"", "e", "english", "ja_JP", "en-", "en--US"} {
+		if Valid(tag) {
+			t.Errorf("Valid(%q) = true", tag)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/xdg"
+)
+
+var (
+	ErrInvalidEntry = errors.New("invalid journal entry")
+)
+
+// Kind distinguishes symptom entries from medication entries.
+type Kind string
+
+const (
+	KindSymptom    Kind = "symptom"
+	KindMedication Kind = "medication"
+)
+
+// Entry is one logged symptom or medication dose.
+type Entry struct {
+	Date     string    `json:"date"` // "2025-06-15"
+	Kind     Kind      `json:"kind"`
+	Name     string    `json:"name"`               // "sneezing", "cetirizine"
+	Severity int       `json:"severity,omitempty"` // 1-5, symptoms only
+	Note     string    `json:"note,omitempty"`
+	LoggedAt time.Time `json:"loggedAt"`
+}
+
+// Journal is an append-only JSON Lines log of symptoms and medications.
+type Journal struct {
+	path string
+	err  error // set when no data directory could be resolved
+}
+
+// New creates a Journal. If path is empty, uses $XDG_DATA_HOME/pollenow/journal.jsonl.
+// If that cannot be resolved, every operation returns the resolution error.
+func New(path string) *Journal {
+	j := &Journal{path: path}
+	if path == "" {
+		data, err := xdg.DataDir()
+		j.path, j.err = filepath.Join(data, "journal.jsonl"), err
+	}
+	return j
+}
+
+// Validate checks that the entry has the fields its kind requires.
+func (e Entry) Validate() error {
+	if _, err := time.Parse(pollen.DateLayout, e.Date); err != nil {
+		return fmt.Errorf("%w: date must be YYYY-MM-DD, got %q", ErrInvalidEntry, e.Date)
+	}
+	if strings.TrimSpace(e.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidEntry)
+	}
+	switch e.Kind {
+	case KindSymptom:
+		if e.Severity < 1 || e.Severity > 5 {
+			return fmt.Errorf("%w: severity must be between 1 and 5", ErrInvalidEntry)
+		}
+	case KindMedication:
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidEntry, e.Kind)
+	}
+	return nil
+}
+
+// Add appends an entry to the journal.
+func (j *Journal) Add(e Entry) error {
+	if j.err != nil {
+		return j.err
+	}
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	if e.LoggedAt.IsZero() {
+		e.LoggedAt = time.Now()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return fmt.Errorf("creating journal dir: %w", err)
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshaling journal entry: %w", err)
+	}
+
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening journal: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing journal: %w", err)
+	}
+	return nil
+}
+
+// Range returns entries dated within [from, to], sorted by date then log time.
+func (j *Journal) Range(from, to time.Time) ([]Entry, error) {
+	if j.err != nil {
+		return nil, j.err
+	}
+	f, err := os.Open(j.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening journal: %w", err)
+	}
+	defer f.Close()
+
+	lo, hi := from.Format(pollen.DateLayout), to.Format(pollen.DateLayout)
+	var entries []Entry
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("parsing journal line %d: %w", n, err)
+		}
+		if e.Date >= lo && e.Date <= hi {
+			entries = append(entries, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		if entries[a].Date != entries[b].Date {
+			return entries[a].Date < entries[b].Date
+		}
+		return entries[a].LoggedAt.Before(entries[b].LoggedAt)
+	})
+	return entries, nil
+}
@@ -0,0 +1,64 @@
+package journal
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAddAndRange(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), "journal.jsonl"))
+
+	entries := []Entry{
+		{Date: "2025-06-16", Kind: KindMedication, Name: "cetirizine"},
+		{Date: "2025-06-15", Kind: KindSymptom, Name: "sneezing", Severity: 3},
+		{Date: "2025-07-01", Kind: KindSymptom, Name: "itchy eyes", Severity: 2},
+	}
+	for _, e := range entries {
+		if err := j.Add(e); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	from := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	got, err := j.Range(from, to)
+	if err != nil {
+		t.Fatalf("Range failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2", len(got))
+	}
+	if got[0].Name != "sneezing" || got[1].Name != "cetirizine" {
+		t.Errorf("entries not sorted by date: %v", got)
+	}
+	if got[0].LoggedAt.IsZero() {
+		t.Error("LoggedAt should be set on Add")
+	}
+}
+
+func TestRangeMissingFile(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), "missing.jsonl"))
+	got, err := j.Range(time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("Range should not fail for missing journal: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %d entries, want 0", len(got))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []Entry{
+		{Date: "06/15/2025", Kind: KindSymptom, Name: "sneezing", Severity: 3},
+		{Date: "2025-06-15", Kind: KindSymptom, Name: "", Severity: 3},
+		{Date: "2025-06-15", Kind: KindSymptom, Name: "sneezing", Severity: 0},
+		{Date: "2025-06-15", Kind: "vitamin", Name: "c"},
+	}
+	for _, e := range tests {
+		if err := e.Validate(); !errors.Is(err, ErrInvalidEntry) {
+			t.Errorf("%+v: expected ErrInvalidEntry, got %v", e, err)
+		}
+	}
+}
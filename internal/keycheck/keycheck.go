@@ -0,0 +1,134 @@
+package keycheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+// probeZIP is geocoded to exercise the Geocoding API. probeLat and probeLng
+// are used for the Pollen API when geocoding fails.
+const (
+	probeZIP = "94025"
+	probeLat = 37.4530
+	probeLng = -122.1817
+)
+
+// Status is the outcome of checking the key against one API.
+type Status int
+
+const (
+	OK         Status = iota
+	InvalidKey        // Google rejected the key itself
+	NotEnabled        // the key is valid but the API is not enabled for its project
+	Failed            // network errors, quota, or anything else
+)
+
+func (s Status) String() string {
+	switch s {
+	case OK:
+		return "ok"
+	case InvalidKey:
+		return "invalid key"
+	case NotEnabled:
+		return "API not enabled"
+	default:
+		return "failed"
+	}
+}
+
+// Result is the outcome for one API.
+type Result struct {
+	API    string // "Geocoding API", "Pollen API"
+	Status Status
+	Err    error // nil when Status is OK
+}
+
+// Hint tells the user how to fix a failed result.
+func (r Result) Hint() string {
+	switch r.Status {
+	case InvalidKey:
+		return "check that the key was copied correctly from the Google Cloud console"
+	case NotEnabled:
+		return fmt.Sprintf("enable the %s for the key's project in the Google Cloud console", r.API)
+	}
+	return ""
+}
+
+// Check makes one live request to each API and reports, per API, whether
+// the key works.
+func Check(ctx context.Context, g geocoding.Geocoder, p pollen.PollenClient) []Result {
+	geo := Result{API: "Geocoding API"}
+	lat, lng := probeLat, probeLng
+	loc, err := g.Geocode(ctx, probeZIP)
+	switch {
+	case err == nil:
+		lat, lng = loc.Lat, loc.Lng
+	case errors.Is(err, geocoding.ErrNoResults):
+		// The request was authorized; the probe just found nothing.
+	default:
+		geo.Status, geo.Err = classifyGeocoding(err), err
+	}
+
+	pol := Result{API: "Pollen API"}
+	if _, err := p.GetForecast(ctx, lat, lng, 1); err != nil {
+		pol.Status, pol.Err = classifyPollen(err), err
+	}
+
+	return []Result{geo, pol}
+}
+
+// AllOK reports whether every result succeeded.
+func AllOK(results []Result) bool {
+	for _, r := range results {
+		if r.Status != OK {
+			return false
+		}
+	}
+	return true
+}
+
+func classifyGeocoding(err error) Status {
+	var apiErr *geocoding.APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != "REQUEST_DENIED" {
+		return Failed
+	}
+	return classifyMessage(apiErr.Message)
+}
+
+func classifyPollen(err error) Status {
+	var apiErr *pollen.APIError
+	if !errors.As(err, &apiErr) {
+		return Failed
+	}
+	for _, r := range apiErr.Reasons {
+		switch r {
+		case "API_KEY_INVALID":
+			return InvalidKey
+		case "SERVICE_DISABLED", "API_KEY_SERVICE_BLOCKED":
+			return NotEnabled
+		}
+	}
+	return classifyMessage(apiErr.Message)
+}
+
+// classifyMessage recognizes the wording Google uses across its APIs.
+func classifyMessage(msg string) Status {
+	msg = strings.ToLower(msg)
+	switch {
+	case strings.Contains(msg, "api key not valid"),
+		strings.Contains(msg, "provided api key is invalid"):
+		return InvalidKey
+	case strings.Contains(msg, "not authorized to use this api"),
+		strings.Contains(msg, "not activated"),
+		strings.Contains(msg, "has not been used"),
+		strings.Contains(msg, "is disabled"),
+		strings.Contains(msg, "are blocked"):
+		return NotEnabled
+	}
+	return Failed
+}
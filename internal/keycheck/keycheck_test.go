@@ -0,0 +1,98 @@
+package keycheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+type mockGeocoder struct {
+	err error
+}
+
+func (m *mockGeocoder) Geocode(ctx context.Context, zip string) (*geocoding.Location, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &geocoding.Location{Lat: 1, Lng: 2}, nil
+}
+
+type mockPollenClient struct {
+	err      error
+	lat, lng float64
+}
+
+func (m *mockPollenClient) GetForecast(ctx context.Context, lat, lng float64, days int) (*pollen.RawForecastResponse, error) {
+	m.lat, m.lng = lat, lng
+	return &pollen.RawForecastResponse{}, m.err
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		geoErr     error
+		pollenErr  error
+		wantGeo    Status
+		wantPollen Status
+	}{
+		{"both ok", nil, nil, OK, OK},
+		{
+			name:       "pollen not enabled",
+			pollenErr:  &pollen.APIError{StatusCode: 403, Status: "PERMISSION_DENIED", Message: "Pollen API has not been used in project 123 before or it is disabled.", Reasons: []string{"SERVICE_DISABLED"}},
+			wantGeo:    OK,
+			wantPollen: NotEnabled,
+		},
+		{
+			name:       "geocoding not enabled",
+			geoErr:     &geocoding.APIError{Status: "REQUEST_DENIED", Message: "This API project is not authorized to use this API."},
+			wantGeo:    NotEnabled,
+			wantPollen: OK,
+		},
+		{
+			name:       "invalid key",
+			geoErr:     &geocoding.APIError{Status: "REQUEST_DENIED", Message: "The provided API key is invalid."},
+			pollenErr:  &pollen.APIError{StatusCode: 400, Status: "INVALID_ARGUMENT", Message: "API key not valid. Please pass a valid API key."},
+			wantGeo:    InvalidKey,
+			wantPollen: InvalidKey,
+		},
+		{
+			name:       "network",
+			geoErr:     errors.New("connection refused"),
+			pollenErr:  errors.New("connection refused"),
+			wantGeo:    Failed,
+			wantPollen: Failed,
+		},
+		{"no results still authorized", geocoding.ErrNoResults, nil, OK, OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Check(context.Background(), &mockGeocoder{err: tt.geoErr}, &mockPollenClient{err: tt.pollenErr})
+			if results[0].Status != tt.wantGeo {
+				t.Errorf("geocoding: got %v, want %v", results[0].Status, tt.wantGeo)
+			}
+			if results[1].Status != tt.wantPollen {
+				t.Errorf("pollen: got %v, want %v", results[1].Status, tt.wantPollen)
+			}
+			if AllOK(results) != (tt.wantGeo == OK && tt.wantPollen == OK) {
+				t.Error("AllOK disagrees with the results")
+			}
+		})
+	}
+}
+
+func TestCheckUsesGeocodedLocation(t *testing.T) {
+	p := &mockPollenClient{}
+	Check(context.Background(), &mockGeocoder{}, p)
+	if p.lat != 1 || p.lng != 2 {
+		t.Errorf("pollen probe used %v,%v, want the geocoded location", p.lat, p.lng)
+	}
+
+	Check(context.Background(), &mockGeocoder{err: errors.New("down")}, p)
+	if p.lat != probeLat || p.lng != probeLng {
+		t.Errorf("pollen probe used %v,%v, want the fallback location", p.lat, p.lng)
+	}
+}
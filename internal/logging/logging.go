@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown log format")
+)
+
+// Log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Discard drops every record. Packages log to it until given a logger.
+var Discard = slog.New(discardHandler{})
+
+// Options configure New.
+type Options struct {
+	Verbosity int    // 0 warnings only, 1 adds requests and retries (-v), 2 adds cache and service detail (-vv)
+	Format    string // FormatText (default) or FormatJSON
+	File      string // append to this file instead of writing to stderr
+}
+
+// Level maps a -v count to the lowest level logged.
+func Level(verbosity int) slog.Level {
+	switch {
+	case verbosity <= 0:
+		return slog.LevelWarn
+	case verbosity == 1:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// New returns a logger for opts, writing to stderr unless opts.File is set.
+// The returned close function releases the log file; it is a no-op for
+// stderr.
+func New(opts Options, stderr io.Writer) (*slog.Logger, func() error, error) {
+	w, closeFn := stderr, func() error { return nil }
+	if opts.File != "" {
+		if err := os.MkdirAll(filepath.Dir(opts.File), 0o755); err != nil {
+			return nil, nil, fmt.Errorf("creating log dir: %w", err)
+		}
+		f, err := os.OpenFile(opts.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening log file: %w", err)
+		}
+		w, closeFn = f, f.Close
+	}
+
+	ho := &slog.HandlerOptions{Level: Level(opts.Verbosity)}
+	var h slog.Handler
+	switch opts.Format {
+	case "", FormatText:
+		h = slog.NewTextHandler(w, ho)
+	case FormatJSON:
+		h = slog.NewJSONHandler(w, ho)
+	default:
+		closeFn()
+		return nil, nil, fmt.Errorf("%w %q (use %s or %s)", ErrUnknownFormat, opts.Format, FormatText, FormatJSON)
+	}
+	return slog.New(h), closeFn, nil
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
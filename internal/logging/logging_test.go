@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+	for v, want := range []string{"warn", "info", "debug"} {
+		buf.Reset()
+		log, _, err := New(Options{Verbosity: v}, &buf)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		log.Debug("debug")
+		log.Info("info")
+		log.Warn("warn")
+		if got := strings.Count(buf.String(), "\n"); got != v+1 {
+			t.Errorf("verbosity %d: got %d lines, want %d (down to %s)", v, got, v+1, want)
+		}
+	}
+}
+
+func TestJSONFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "logs", "pollenow.log")
+	log, closeFn, err := New(Options{Verbosity: 1, Format: FormatJSON, File: p}, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	log.Info("api request", "status", 200)
+	if err := closeFn(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec map[string]any
+	if err := json.Unmarshal(data, &rec); err != nil {
+		t.Fatalf("not JSON: %s", data)
+	}
+	if rec["msg"] != "api request" || rec["status"] != 200.0 {
+		t.Errorf("got %v", rec)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if _, _, err := New(Options{Format: "xml"}, nil); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+}
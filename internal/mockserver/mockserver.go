@@ -0,0 +1,329 @@
+package mockserver
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shunito/pollenow/internal/timezone"
+)
+
+var (
+	ErrUnknownScenario = errors.New("unknown scenario")
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Scenario selects how the server answers.
+type Scenario string
+
+const (
+	Normal      Scenario = "normal"       // typical early-summer forecast
+	HighTree    Scenario = "high-tree"    // tree pollen very high every day
+	NoData      Scenario = "no-data"      // nothing in season
+	RateLimit   Scenario = "rate-limit"   // Pollen API answers 429
+	ServerError Scenario = "server-error" // Pollen API answers 500
+	Slow        Scenario = "slow"         // normal, after a delay
+)
+
+// Scenarios lists every scenario with a one-line description, in the
+// order shown by help output.
+var Scenarios = []struct {
+	Name        Scenario
+	Description string
+}{
+	{Normal, "typical early-summer forecast"},
+	{HighTree, "tree pollen very high all week"},
+	{NoData, "no pollen type in season"},
+	{RateLimit, "Pollen API answers 429 with Retry-After"},
+	{ServerError, "Pollen API answers 500"},
+	{Slow, "normal forecast after a delay"},
+}
+
+// ParseScenario returns the scenario named s.
+func ParseScenario(s string) (Scenario, error) {
+	for _, sc := range Scenarios {
+		if string(sc.Name) == s {
+			return sc.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q", ErrUnknownScenario, s)
+}
+
+// DefaultDelay is how long the slow scenario waits before answering.
+const DefaultDelay = 3 * time.Second
+
+// Server answers Geocoding and Pollen API requests from embedded fixtures.
+// It matches requests by path, so any host works, and a leading path
+// segment naming a scenario overrides the default for that request:
+// /high-tree/v1/forecast:lookup. Error scenarios only affect the Pollen
+// API; geocoding always succeeds so the failure shows where it matters.
+type Server struct {
+	scenario Scenario
+	delay    time.Duration
+	now      func() time.Time
+}
+
+// New creates a Server answering with scenario by default. A zero delay
+// uses DefaultDelay.
+func New(scenario Scenario, delay time.Duration) *Server {
+	if delay <= 0 {
+		delay = DefaultDelay
+	}
+	return &Server{scenario: scenario, delay: delay, now: time.Now}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	scenario, path := s.scenario, r.URL.Path
+	if first, rest, ok := strings.Cut(strings.TrimPrefix(path, "/"), "/"); ok {
+		if sc, err := ParseScenario(first); err == nil {
+			scenario, path = sc, "/"+rest
+		}
+	}
+
+	if scenario == Slow {
+		select {
+		case <-time.After(s.delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(path, "/geocode/json"):
+		s.geocode(w, r)
+	case strings.HasSuffix(path, "forecast:lookup"):
+		s.forecast(w, r, scenario)
+	case strings.Contains(path, "/heatmapTiles/"):
+		s.heatmapTile(w, path, scenario)
+	default:
+		writeGoogleError(w, http.StatusNotFound, "NOT_FOUND", "no mock for "+path)
+	}
+}
+
+// places gives a few well-known ZIP codes their real names; any other
+// valid ZIP geocodes to a placeholder near Menlo Park.
+var places = map[string]struct {
+	name     string
+	lat, lng float64
+}{
+	"94025": {"Menlo Park, CA 94025, USA", 37.4530, -122.1817},
+	"10001": {"New York, NY 10001, USA", 40.7506, -73.9972},
+	"60601": {"Chicago, IL 60601, USA", 41.8864, -87.6186},
+	"98101": {"Seattle, WA 98101, USA", 47.6101, -122.3344},
+}
+
+func (s *Server) geocode(w http.ResponseWriter, r *http.Request) {
+	zip := r.URL.Query().Get("address")
+	if zip == "00000" {
+		writeJSON(w, http.StatusOK, map[string]any{"results": []any{}, "status": "ZERO_RESULTS"})
+		return
+	}
+	p, ok := places[zip]
+	if !ok {
+		p = places["94025"]
+		p.name = fmt.Sprintf("Mocktown, CA %s, USA", zip)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status": "OK",
+		"results": []any{map[string]any{
+			"formatted_address": p.name,
+			"geometry":          map[string]any{"location": map[string]float64{"lat": p.lat, "lng": p.lng}},
+		}},
+	})
+}
+
+// pollenError answers with the failure an error scenario calls for and
+// reports whether it did.
+func pollenError(w http.ResponseWriter, scenario Scenario) bool {
+	switch scenario {
+	case RateLimit:
+		w.Header().Set("Retry-After", "1")
+		writeGoogleError(w, http.StatusTooManyRequests, "RESOURCE_EXHAUSTED",
+			"Quota exceeded for quota metric 'Requests' of service 'pollen.googleapis.com'.")
+		return true
+	case ServerError:
+		writeGoogleError(w, http.StatusInternalServerError, "INTERNAL",
+			"Internal error encountered.")
+		return true
+	}
+	return false
+}
+
+func (s *Server) forecast(w http.ResponseWriter, r *http.Request, scenario Scenario) {
+	if pollenError(w, scenario) {
+		return
+	}
+
+	name := "forecast_5day.json"
+	switch scenario {
+	case HighTree:
+		name = "forecast_high_tree.json"
+	case NoData:
+		name = "forecast_no_data.json"
+	}
+	days, err := strconv.Atoi(r.URL.Query().Get("days"))
+	if err != nil || days < 1 || days > 5 {
+		writeGoogleError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "days must be between 1 and 5")
+		return
+	}
+
+	// Like the real API, days start from the date at the queried location.
+	q := r.URL.Query()
+	lat, _ := strconv.ParseFloat(q.Get("location.latitude"), 64)
+	lng, _ := strconv.ParseFloat(q.Get("location.longitude"), 64)
+	tz, _ := timezone.Embedded{}.TimeZone(r.Context(), lat, lng)
+
+	body, err := loadForecast(name, timezone.Today(tz, s.now()), days)
+	if err != nil {
+		writeGoogleError(w, http.StatusInternalServerError, "INTERNAL", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// upiColors approximate the heatmap palette for index values 1 through 5.
+var upiColors = []color.NRGBA{
+	{0x00, 0x9e, 0x3a, 0xa0},
+	{0x84, 0xcf, 0x33, 0xa0},
+	{0xff, 0xd0, 0x00, 0xa0},
+	{0xff, 0x84, 0x00, 0xa0},
+	{0xe5, 0x1f, 0x1f, 0xa0},
+}
+
+// heatmapTile draws a 256x256 tile from a smooth made-up field in world
+// coordinates, so neighboring tiles line up at any zoom. The no-data
+// scenario draws nothing and high-tree raises tree pollen.
+func (s *Server) heatmapTile(w http.ResponseWriter, path string, scenario Scenario) {
+	if pollenError(w, scenario) {
+		return
+	}
+
+	// .../mapTypes/{type}/heatmapTiles/{z}/{x}/{y}
+	parts := strings.Split(path, "/")
+	if len(parts) < 6 || parts[len(parts)-4] != "heatmapTiles" {
+		writeGoogleError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "malformed tile path")
+		return
+	}
+	mapType := parts[len(parts)-5]
+	var zxy [3]int
+	for i := range zxy {
+		n, err := strconv.Atoi(parts[len(parts)-3+i])
+		if err != nil || n < 0 {
+			writeGoogleError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "malformed tile path")
+			return
+		}
+		zxy[i] = n
+	}
+	z, tx, ty := zxy[0], zxy[1], zxy[2]
+	if z > 16 || tx >= 1<<z || ty >= 1<<z {
+		writeGoogleError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "tile outside the grid")
+		return
+	}
+
+	var phase, boost float64
+	switch mapType {
+	case "TREE_UPI":
+		if scenario == HighTree {
+			boost = 2
+		}
+	case "GRASS_UPI":
+		phase = 1.7
+	case "WEED_UPI":
+		phase = 3.1
+	default:
+		writeGoogleError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "unknown map type "+mapType)
+		return
+	}
+
+	const size = 256
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	if scenario != NoData {
+		world := float64(size) * math.Exp2(float64(z))
+		for py := 0; py < size; py++ {
+			wy := (float64(ty*size+py) + 0.5) / world
+			for px := 0; px < size; px++ {
+				wx := (float64(tx*size+px) + 0.5) / world
+				v := 2.5 + 1.5*math.Sin(wx*2*math.Pi*40+phase) + math.Cos(wy*2*math.Pi*55-phase) + boost
+				upi := min(5, int(v))
+				if upi >= 1 {
+					img.SetNRGBA(px, py, upiColors[upi-1])
+				}
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "image/png")
+	png.Encode(w, img)
+}
+
+// loadForecast loads the named fixture, keeps at most days entries, and dates
+// them consecutively from today so the output always looks current.
+func loadForecast(name string, today time.Time, days int) ([]byte, error) {
+	data, err := fixtures.ReadFile("fixtures/" + name)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture: %w", err)
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", name, err)
+	}
+
+	daily, _ := resp["dailyInfo"].([]any)
+	if len(daily) > days {
+		daily = daily[:days]
+	}
+	for i, d := range daily {
+		day, ok := d.(map[string]any)
+		if !ok {
+			continue
+		}
+		t := today.AddDate(0, 0, i)
+		day["date"] = map[string]int{"year": t.Year(), "month": int(t.Month()), "day": t.Day()}
+	}
+	resp["dailyInfo"] = daily
+	return json.Marshal(resp)
+}
+
+// RoundTripper serves requests directly from s without opening a socket,
+// for running the CLI against the mock in-process.
+func (s *Server) RoundTripper() http.RoundTripper {
+	return roundTripper{s}
+}
+
+type roundTripper struct{ h http.Handler }
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	rt.h.ServeHTTP(rec, req)
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	resp := rec.Result()
+	resp.Request = req
+	return resp, nil
+}
+
+func writeGoogleError(w http.ResponseWriter, code int, status, message string) {
+	writeJSON(w, code, map[string]any{
+		"error": map[string]any{"code": code, "message": message, "status": status},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
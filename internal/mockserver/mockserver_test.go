@@ -0,0 +1,151 @@
+package mockserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+func clients(srv *httptest.Server, prefix string) (*geocoding.GoogleGeocoder, *pollen.GooglePollenClient) {
+	g := geocoding.NewGoogleGeocoder("test-key", srv.Client()).WithBaseURL(srv.URL + prefix + "/maps/api/geocode/json")
+	p := pollen.NewGooglePollenClient("test-key", srv.Client()).WithBaseURL(srv.URL + prefix + "/v1/forecast:lookup")
+	return g, p
+}
+
+func TestNormal(t *testing.T) {
+	s := New(Normal, 0)
+	s.now = func() time.Time { return time.Date(2026, 4, 30, 9, 0, 0, 0, time.Local) }
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	g, p := clients(srv, "")
+
+	loc, err := g.Geocode(context.Background(), "10001")
+	if err != nil {
+		t.Fatalf("Geocode failed: %v", err)
+	}
+	if loc.DisplayName != "New York, NY 10001, USA" {
+		t.Errorf("DisplayName: got %q", loc.DisplayName)
+	}
+
+	raw, err := p.GetForecast(context.Background(), loc.Lat, loc.Lng, 3)
+	if err != nil {
+		t.Fatalf("GetForecast failed: %v", err)
+	}
+	if len(raw.DailyInfo) != 3 {
+		t.Fatalf("got %d days, want 3", len(raw.DailyInfo))
+	}
+	// Dates start today and roll over the month end.
+	if d := raw.DailyInfo[0].Date; d != (pollen.DateInfo{Year: 2026, Month: 4, Day: 30}) {
+		t.Errorf("first day: got %+v", d)
+	}
+	if d := raw.DailyInfo[1].Date; d != (pollen.DateInfo{Year: 2026, Month: 5, Day: 1}) {
+		t.Errorf("second day: got %+v", d)
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	srv := httptest.NewServer(New(Normal, 0))
+	defer srv.Close()
+
+	_, p := clients(srv, "/high-tree")
+	raw, err := p.GetForecast(context.Background(), 37.45, -122.18, 1)
+	if err != nil {
+		t.Fatalf("high-tree: %v", err)
+	}
+	for _, info := range raw.DailyInfo[0].PollenTypeInfo {
+		if info.Code == "TREE" && (info.IndexInfo == nil || info.IndexInfo.Value != 5) {
+			t.Errorf("high-tree: tree index %+v", info.IndexInfo)
+		}
+	}
+
+	_, p = clients(srv, "/no-data")
+	raw, err = p.GetForecast(context.Background(), 37.45, -122.18, 5)
+	if err != nil {
+		t.Fatalf("no-data: %v", err)
+	}
+	if f := pollen.FormatForecast(raw, time.Now()); len(f.Days) != 1 {
+		t.Errorf("no-data: got %d days", len(f.Days))
+	}
+
+	for prefix, status := range map[string]int{"/rate-limit": 429, "/server-error": 500} {
+		g, p := clients(srv, prefix)
+		if _, err := g.Geocode(context.Background(), "94025"); err != nil {
+			t.Errorf("%s: geocoding should still succeed, got %v", prefix, err)
+		}
+		_, err := p.GetForecast(context.Background(), 37.45, -122.18, 1)
+		var apiErr *pollen.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != status {
+			t.Errorf("%s: got %v, want status %d", prefix, err, status)
+		}
+	}
+
+	g, _ := clients(srv, "")
+	if _, err := g.Geocode(context.Background(), "00000"); !errors.Is(err, geocoding.ErrNoResults) {
+		t.Errorf("00000: expected ErrNoResults, got %v", err)
+	}
+}
+
+func TestHeatmapTile(t *testing.T) {
+	srv := httptest.NewServer(New(Normal, 0))
+	defer srv.Close()
+	_, p := clients(srv, "")
+
+	data, err := p.HeatmapTile(context.Background(), pollen.MapTypeTree, 8, 41, 99)
+	if err != nil {
+		t.Fatalf("HeatmapTile failed: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("not a PNG: %v", err)
+	}
+	if got := img.Bounds().Dx(); got != 256 {
+		t.Errorf("width: got %d", got)
+	}
+
+	_, p = clients(srv, "/no-data")
+	data, err = p.HeatmapTile(context.Background(), pollen.MapTypeTree, 8, 41, 99)
+	if err != nil {
+		t.Fatalf("no-data: %v", err)
+	}
+	img, _ = png.Decode(bytes.NewReader(data))
+	if _, _, _, a := img.At(128, 128).RGBA(); a != 0 {
+		t.Errorf("no-data tile should be transparent, got alpha %d", a)
+	}
+
+	_, p = clients(srv, "/server-error")
+	var apiErr *pollen.APIError
+	if _, err := p.HeatmapTile(context.Background(), pollen.MapTypeTree, 8, 41, 99); !errors.As(err, &apiErr) || apiErr.StatusCode != 500 {
+		t.Errorf("server-error: got %v", err)
+	}
+}
+
+func TestSlowRespectsCancellation(t *testing.T) {
+	s := New(Slow, time.Minute)
+	client := &http.Client{Transport: s.RoundTripper(), Timeout: 50 * time.Millisecond}
+	p := pollen.NewGooglePollenClient("test-key", client)
+
+	start := time.Now()
+	if _, err := p.GetForecast(context.Background(), 37.45, -122.18, 1); err == nil {
+		t.Fatal("expected a timeout")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("slow response ignored the client timeout")
+	}
+}
+
+func TestParseScenario(t *testing.T) {
+	if sc, err := ParseScenario("rate-limit"); err != nil || sc != RateLimit {
+		t.Errorf("rate-limit: got %q, %v", sc, err)
+	}
+	if _, err := ParseScenario("meteor"); !errors.Is(err, ErrUnknownScenario) {
+		t.Errorf("expected ErrUnknownScenario, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/i18n"
+	"github.com/shunito/pollenow/internal/journal"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+// exposureHour is when outdoor exposure is assumed to begin on a forecast day.
+const exposureHour = 8
+
+// Advice is a recommendation to take a medication ahead of a high-pollen day.
+type Advice struct {
+	Medication string
+	Date       string // forecast day the dose protects, "2025-06-16"
+	DayName    string // "Today", "Tomorrow", "Wednesday"
+	Triggers   []pollen.TypeLevel
+	TakeAt     time.Time
+	Taken      bool
+}
+
+// Build returns the next dose for each medication whose targets reach the
+// high threshold somewhere in the forecast. Medications with nothing to
+// react to are omitted.
+func Build(meds []config.Medication, fc *pollen.Forecast, th pollen.Thresholds, now time.Time) ([]Advice, error) {
+	var advice []Advice
+	if fc == nil {
+		return advice, nil
+	}
+
+	for _, m := range meds {
+		if err := m.Validate(); err != nil {
+			return nil, err
+		}
+		lead, _ := m.Lead()
+
+		for _, day := range fc.Days {
+			triggers := th.HighTargets(day, m.Targets)
+			if len(triggers) == 0 {
+				continue
+			}
+			date, err := time.ParseInLocation(pollen.DateLayout, day.Date, now.Location())
+			if err != nil {
+				return nil, fmt.Errorf("forecast day %q: %w", day.Date, err)
+			}
+			// Skip days that are already over.
+			if date.AddDate(0, 0, 1).Before(now) {
+				continue
+			}
+			exposure := date.Add(exposureHour * time.Hour)
+			advice = append(advice, Advice{
+				Medication: m.Name,
+				Date:       day.Date,
+				DayName:    day.DayName,
+				Triggers:   triggers,
+				TakeAt:     exposure.Add(-lead),
+			})
+			break
+		}
+	}
+
+	return advice, nil
+}
+
+// ApplyJournal marks advice as taken when the journal has a matching
+// medication entry dated between the dose day and the day it protects.
+func ApplyJournal(advice []Advice, entries []journal.Entry) {
+	for i := range advice {
+		from := advice[i].TakeAt.Format(pollen.DateLayout)
+		for _, e := range entries {
+			if e.Kind != journal.KindMedication || !strings.EqualFold(e.Name, advice[i].Medication) {
+				continue
+			}
+			if e.Date >= from && e.Date <= advice[i].Date {
+				advice[i].Taken = true
+				break
+			}
+		}
+	}
+}
+
+// Message renders the advice as a sentence in p's language, e.g.
+// "Take cetirizine tonight — tomorrow Tree is HIGH".
+func (a Advice) Message(p *i18n.Printer, now time.Time) string {
+	parts := make([]string, 0, len(a.Triggers))
+	for _, t := range a.Triggers {
+		parts = append(parts, p.T("plan.trigger", typeName(p, t), strings.ToUpper(t.Level.Category)))
+	}
+	return p.T("plan.advice", a.Medication, When(p, a.TakeAt, now), dayPhrase(p, a.Date, now), strings.Join(parts, ", "))
+}
+
+// When describes a dose time relative to now: "now", "this afternoon",
+// "tonight", "tomorrow morning", "Wednesday night".
+func When(p *i18n.Printer, at, now time.Time) string {
+	if !at.After(now) {
+		return p.T("plan.when.now")
+	}
+
+	switch part := partOfDay(at); daysBetween(now, at) {
+	case 0:
+		if part == "night" {
+			return p.T("plan.when.tonight")
+		}
+		return p.T("plan.when.this_" + part)
+	case 1:
+		return p.T("plan.when.tomorrow_" + part)
+	default:
+		return p.T("plan.when.day_"+part, p.Weekday(at.Weekday()))
+	}
+}
+
+// daysBetween counts calendar days from now to t in now's time zone.
+func daysBetween(now, t time.Time) int {
+	y1, m1, d1 := now.Date()
+	y2, m2, d2 := t.In(now.Location()).Date()
+	today := time.Date(y1, m1, d1, 0, 0, 0, 0, now.Location())
+	day := time.Date(y2, m2, d2, 0, 0, 0, 0, now.Location())
+	return int(day.Sub(today).Hours()/24 + 0.5)
+}
+
+func partOfDay(t time.Time) string {
+	switch h := t.Hour(); {
+	case h < 12:
+		return "morning"
+	case h < 17:
+		return "afternoon"
+	default:
+		return "night"
+	}
+}
+
+// dayPhrase names the forecast day a dose protects: "today", "tomorrow",
+// "on Wednesday".
+func dayPhrase(p *i18n.Printer, date string, now time.Time) string {
+	d, err := time.ParseInLocation(pollen.DateLayout, date, now.Location())
+	if err != nil {
+		return p.T("plan.on.unknown")
+	}
+	switch daysBetween(now, d) {
+	case 0:
+		return p.T("plan.on.today")
+	case 1:
+		return p.T("plan.on.tomorrow")
+	default:
+		return p.T("plan.on.day", p.Weekday(d.Weekday()))
+	}
+}
+
+// typeName translates pollen type names; plant names are left as the API
+// gave them.
+func typeName(p *i18n.Printer, t pollen.TypeLevel) string {
+	switch code := strings.ToLower(t.Code); code {
+	case "grass", "tree", "weed":
+		return p.T("type." + code)
+	default:
+		return t.Name
+	}
+}
@@ -0,0 +1,98 @@
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shunito/pollenow/internal/config"
+	"github.com/shunito/pollenow/internal/i18n"
+	"github.com/shunito/pollenow/internal/journal"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+func lvl(v int, cat string) pollen.PollenLevel {
+	return pollen.PollenLevel{Level: &v, Category: cat}
+}
+
+func testForecast() *pollen.Forecast {
+	return &pollen.Forecast{Days: []pollen.DayForecast{
+		{Date: "2025-06-15", DayName: "Today", Tree: lvl(2, "Low"), Grass: lvl(1, "Very Low")},
+		{Date: "2025-06-16", DayName: "Tomorrow", Tree: lvl(4, "High"), Grass: lvl(1, "Very Low")},
+		{Date: "2025-06-17", DayName: "Tuesday", Tree: lvl(3, "Moderate"), Grass: lvl(5, "Very High")},
+	}}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2025, 6, 15, 18, 30, 0, 0, time.UTC)
+	meds := []config.Medication{
+		{Name: "cetirizine", Targets: []string{"tree"}, LeadTime: "12h"},
+		{Name: "fluticasone", Targets: []string{"grass"}, LeadTime: "2h"},
+		{Name: "loratadine", Targets: []string{"weed"}},
+	}
+
+	advice, err := Build(meds, testForecast(), pollen.DefaultThresholds, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(advice) != 2 {
+		t.Fatalf("got %d advice entries, want 2", len(advice))
+	}
+
+	if got := advice[0].Message(i18n.English, now); got != "Take cetirizine tonight — tomorrow Tree is HIGH" {
+		t.Errorf("cetirizine message: got %q", got)
+	}
+	if got := advice[1].Message(i18n.English, now); got != "Take fluticasone Tuesday morning — on Tuesday Grass is VERY HIGH" {
+		t.Errorf("fluticasone message: got %q", got)
+	}
+	if got := advice[0].Message(i18n.New("es"), now); got != "Toma cetirizine esta noche — mañana Árboles está HIGH" {
+		t.Errorf("cetirizine message in Spanish: got %q", got)
+	}
+}
+
+func TestBuildInvalidMedication(t *testing.T) {
+	meds := []config.Medication{{Name: "cetirizine", Targets: []string{"tree"}, LeadTime: "soon"}}
+	if _, err := Build(meds, testForecast(), pollen.DefaultThresholds, time.Now()); err == nil {
+		t.Error("expected error for invalid lead time")
+	}
+}
+
+func TestApplyJournal(t *testing.T) {
+	now := time.Date(2025, 6, 15, 18, 30, 0, 0, time.UTC)
+	meds := []config.Medication{{Name: "cetirizine", Targets: []string{"tree"}}}
+	advice, _ := Build(meds, testForecast(), pollen.DefaultThresholds, now)
+
+	ApplyJournal(advice, []journal.Entry{
+		{Date: "2025-06-14", Kind: journal.KindMedication, Name: "cetirizine"},
+		{Date: "2025-06-15", Kind: journal.KindSymptom, Name: "cetirizine", Severity: 1},
+	})
+	if advice[0].Taken {
+		t.Error("should not be taken: only an earlier dose and a symptom entry")
+	}
+
+	ApplyJournal(advice, []journal.Entry{
+		{Date: "2025-06-15", Kind: journal.KindMedication, Name: "Cetirizine"},
+	})
+	if !advice[0].Taken {
+		t.Error("should be taken after logging a dose on the dose day")
+	}
+}
+
+func TestWhen(t *testing.T) {
+	now := time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{now.Add(-time.Hour), "now"},
+		{time.Date(2025, 6, 15, 11, 0, 0, 0, time.UTC), "this morning"},
+		{time.Date(2025, 6, 15, 14, 0, 0, 0, time.UTC), "this afternoon"},
+		{time.Date(2025, 6, 15, 21, 0, 0, 0, time.UTC), "tonight"},
+		{time.Date(2025, 6, 16, 7, 0, 0, 0, time.UTC), "tomorrow morning"},
+		{time.Date(2025, 6, 18, 20, 0, 0, 0, time.UTC), "Wednesday night"},
+	}
+	for _, tt := range tests {
+		if got := When(i18n.English, tt.at, now); got != tt.want {
+			t.Errorf("When(%v): got %q, want %q", tt.at, got, tt.want)
+		}
+	}
+}
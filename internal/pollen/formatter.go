@@ -3,10 +3,14 @@ package pollen
 import (
 	"fmt"
 	"time"
+
+	"github.com/shunito/pollenow/internal/i18n"
 )
 
-// FormatForecast transforms the raw API response into a display-ready Forecast.
-func FormatForecast(raw *RawForecastResponse) *Forecast {
+// FormatForecast transforms the raw API response into a display-ready
+// Forecast. Days are named relative to today, which should be the current
+// date in the forecast location's time zone.
+func FormatForecast(raw *RawForecastResponse, today time.Time) *Forecast {
 	if raw == nil {
 		return &Forecast{RegionCode: "Unknown"}
 	}
@@ -17,9 +21,9 @@ func FormatForecast(raw *RawForecastResponse) *Forecast {
 	}
 
 	days := make([]DayForecast, 0, len(raw.DailyInfo))
-	for i, day := range raw.DailyInfo {
+	for _, day := range raw.DailyInfo {
 		dateStr := fmt.Sprintf("%d-%02d-%02d", day.Date.Year, day.Date.Month, day.Date.Day)
-		dayName := getDayName(i, day.Date)
+		dayName := getDayName(day.Date, today)
 
 		days = append(days, DayForecast{
 			Date:                  dateStr,
@@ -27,6 +31,7 @@ func FormatForecast(raw *RawForecastResponse) *Forecast {
 			Grass:                 extractPollenLevel(day.PollenTypeInfo, "GRASS"),
 			Tree:                  extractPollenLevel(day.PollenTypeInfo, "TREE"),
 			Weed:                  extractPollenLevel(day.PollenTypeInfo, "WEED"),
+			Plants:                extractPlantLevels(day.PlantInfo),
 			HealthRecommendations: extractHealthRecommendations(day.PollenTypeInfo),
 		})
 	}
@@ -53,6 +58,7 @@ func extractPollenLevel(pollenTypes []PollenTypeInfo, code string) PollenLevel {
 				Level:    &level,
 				Category: p.IndexInfo.Category,
 				InSeason: p.InSeason,
+				Color:    levelColor(p.IndexInfo),
 			}
 		}
 	}
@@ -63,6 +69,39 @@ func extractPollenLevel(pollenTypes []PollenTypeInfo, code string) PollenLevel {
 	}
 }
 
+// levelColor returns the API's color for an index as "#rrggbb", or ""
+// when the response has none.
+func levelColor(info *IndexInfo) string {
+	if info.Color == (Color{}) {
+		return ""
+	}
+	return info.Color.Hex()
+}
+
+// extractPlantLevels converts plant species data into PlantLevels.
+func extractPlantLevels(plants []PlantInfo) []PlantLevel {
+	if len(plants) == 0 {
+		return nil
+	}
+
+	levels := make([]PlantLevel, 0, len(plants))
+	for _, p := range plants {
+		level := PollenLevel{Category: "No Data", InSeason: p.InSeason}
+		if p.IndexInfo != nil {
+			value := p.IndexInfo.Value
+			level.Level = &value
+			level.Category = p.IndexInfo.Category
+			level.Color = levelColor(p.IndexInfo)
+		}
+		levels = append(levels, PlantLevel{
+			Code:        p.Code,
+			DisplayName: p.DisplayName,
+			PollenLevel: level,
+		})
+	}
+	return levels
+}
+
 // extractHealthRecommendations collects, deduplicates, and limits recommendations.
 func extractHealthRecommendations(pollenTypes []PollenTypeInfo) []string {
 	seen := make(map[string]bool)
@@ -83,16 +122,24 @@ func extractHealthRecommendations(pollenTypes []PollenTypeInfo) []string {
 	return recommendations
 }
 
-// getDayName returns "Today", "Tomorrow", or the weekday name.
-// Uses index-based naming to avoid timezone issues.
-func getDayName(index int, date DateInfo) string {
-	switch index {
-	case 0:
-		return "Today"
-	case 1:
-		return "Tomorrow"
-	default:
-		t := time.Date(date.Year, time.Month(date.Month), date.Day, 0, 0, 0, 0, time.UTC)
-		return t.Weekday().String()
+// Relabel renames each day relative to today, for a forecast formatted on
+// an earlier date, such as one read back from the cache after midnight.
+func (f *Forecast) Relabel(today time.Time) {
+	for i, d := range f.Days {
+		if t, err := time.Parse(DateLayout, d.Date); err == nil {
+			f.Days[i].DayName = i18n.English.DayName(t, today)
+		}
 	}
 }
+
+// DateLayout is the layout of DayForecast.Date.
+const DateLayout = "2006-01-02"
+
+// getDayName returns "Yesterday", "Today", "Tomorrow", or the weekday name
+// of date as seen from today. Formatted forecasts are cached and stored in
+// history, so names are always English; the UI translates them for display
+// with i18n.Printer.DayName.
+func getDayName(date DateInfo, today time.Time) string {
+	t := time.Date(date.Year, time.Month(date.Month), date.Day, 0, 0, 0, 0, time.UTC)
+	return i18n.English.DayName(t, today)
+}
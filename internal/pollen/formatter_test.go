@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"testing"
+	"time"
 )
 
 func loadTestData(t *testing.T, filename string) *RawForecastResponse {
@@ -21,7 +22,7 @@ func loadTestData(t *testing.T, filename string) *RawForecastResponse {
 
 func TestFormatForecast5Day(t *testing.T) {
 	raw := loadTestData(t, "forecast_5day.json")
-	forecast := FormatForecast(raw)
+	forecast := FormatForecast(raw, time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC))
 
 	if forecast.RegionCode != "US" {
 		t.Errorf("RegionCode: got %q, want %q", forecast.RegionCode, "US")
@@ -65,6 +66,9 @@ func TestFormatForecast5Day(t *testing.T) {
 	if !day0.Grass.InSeason {
 		t.Error("Day0 Grass should be in season")
 	}
+	if day0.Grass.Color != "#008a00" {
+		t.Errorf("Day0 Grass color: got %q, want %q", day0.Grass.Color, "#008a00")
+	}
 
 	if day0.Tree.Level == nil || *day0.Tree.Level != 4 {
 		t.Errorf("Day0 Tree level: got %v, want 4", day0.Tree.Level)
@@ -72,6 +76,9 @@ func TestFormatForecast5Day(t *testing.T) {
 	if day0.Tree.Category != "High" {
 		t.Errorf("Day0 Tree category: got %q, want %q", day0.Tree.Category, "High")
 	}
+	if day0.Tree.Color != "#ff0000" {
+		t.Errorf("Day0 Tree color: got %q, want %q", day0.Tree.Color, "#ff0000")
+	}
 
 	if day0.Weed.Level == nil || *day0.Weed.Level != 0 {
 		t.Errorf("Day0 Weed level: got %v, want 0", day0.Weed.Level)
@@ -88,7 +95,7 @@ func TestFormatForecast5Day(t *testing.T) {
 
 func TestFormatForecastNoData(t *testing.T) {
 	raw := loadTestData(t, "forecast_no_data.json")
-	forecast := FormatForecast(raw)
+	forecast := FormatForecast(raw, time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC))
 
 	if len(forecast.Days) != 1 {
 		t.Fatalf("Days: got %d, want 1", len(forecast.Days))
@@ -115,7 +122,7 @@ func TestFormatForecastNoData(t *testing.T) {
 }
 
 func TestFormatForecastNil(t *testing.T) {
-	forecast := FormatForecast(nil)
+	forecast := FormatForecast(nil, time.Now())
 	if forecast.RegionCode != "Unknown" {
 		t.Errorf("RegionCode: got %q, want %q", forecast.RegionCode, "Unknown")
 	}
@@ -158,20 +165,59 @@ func TestExtractPollenLevelMissing(t *testing.T) {
 }
 
 func TestGetDayName(t *testing.T) {
+	today := time.Date(2025, 6, 15, 22, 0, 0, 0, time.UTC)
 	tests := []struct {
-		index    int
 		date     DateInfo
 		expected string
 	}{
-		{0, DateInfo{2025, 6, 15}, "Today"},
-		{1, DateInfo{2025, 6, 16}, "Tomorrow"},
-		{2, DateInfo{2025, 6, 17}, "Tuesday"}, // June 17, 2025 is a Tuesday
+		{DateInfo{2025, 6, 14}, "Yesterday"},
+		{DateInfo{2025, 6, 15}, "Today"},
+		{DateInfo{2025, 6, 16}, "Tomorrow"},
+		{DateInfo{2025, 6, 17}, "Tuesday"}, // June 17, 2025 is a Tuesday
 	}
 
 	for _, tt := range tests {
-		got := getDayName(tt.index, tt.date)
+		got := getDayName(tt.date, today)
 		if got != tt.expected {
-			t.Errorf("getDayName(%d, %v): got %q, want %q", tt.index, tt.date, got, tt.expected)
+			t.Errorf("getDayName(%v): got %q, want %q", tt.date, got, tt.expected)
+		}
+	}
+}
+
+func TestRelabel(t *testing.T) {
+	raw := loadTestData(t, "forecast_5day.json")
+	forecast := FormatForecast(raw, time.Date(2025, 6, 15, 9, 0, 0, 0, time.UTC))
+
+	// Read back the next day, the first day is now yesterday.
+	forecast.Relabel(time.Date(2025, 6, 16, 0, 30, 0, 0, time.UTC))
+	want := []string{"Yesterday", "Today", "Tomorrow"}
+	for i, w := range want {
+		if got := forecast.Days[i].DayName; got != w {
+			t.Errorf("Days[%d].DayName: got %q, want %q", i, got, w)
 		}
 	}
 }
+
+func TestExtractPlantLevels(t *testing.T) {
+	plants := []PlantInfo{
+		{Code: "OAK", DisplayName: "Oak", InSeason: true, IndexInfo: &IndexInfo{Value: 3, Category: "Moderate"}},
+		{Code: "RAGWEED", DisplayName: "Ragweed", InSeason: false},
+	}
+
+	levels := extractPlantLevels(plants)
+	if len(levels) != 2 {
+		t.Fatalf("got %d plants, want 2", len(levels))
+	}
+	if levels[0].Level == nil || *levels[0].Level != 3 {
+		t.Errorf("Oak level: got %v, want 3", levels[0].Level)
+	}
+	if levels[1].Level != nil || levels[1].Category != "No Data" {
+		t.Errorf("Ragweed: got level %v category %q, want nil/No Data", levels[1].Level, levels[1].Category)
+	}
+
+	day := DayForecast{Plants: levels}
+	names := day.InSeasonPlants()
+	if len(names) != 1 || names[0] != "Oak" {
+		t.Errorf("InSeasonPlants: got %v, want [Oak]", names)
+	}
+}
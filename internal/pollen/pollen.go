@@ -5,16 +5,53 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/shunito/pollenow/internal/apikey"
+	"github.com/shunito/pollenow/internal/httpx"
+	"github.com/shunito/pollenow/internal/i18n"
+	"github.com/shunito/pollenow/internal/logging"
 )
 
+var tracer = otel.Tracer("github.com/shunito/pollenow/internal/pollen")
+
 var (
 	ErrNoAPIKey    = errors.New("no API key configured")
 	ErrInvalidDays = errors.New("days must be between 1 and 5")
 	ErrAPIRequest  = errors.New("pollen API request failed")
 )
 
-const baseURL = "https://pollen.googleapis.com/v1/forecast:lookup"
+// APIError is returned when the Pollen API answers with an error status.
+// It matches ErrAPIRequest with errors.Is.
+type APIError struct {
+	StatusCode int
+	Status     string // "PERMISSION_DENIED", "INVALID_ARGUMENT", ...
+	Message    string
+	Reasons    []string // from error details, e.g. "SERVICE_DISABLED"
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%v: %s", ErrAPIRequest, e.Message)
+	}
+	return fmt.Sprintf("%v: status %d", ErrAPIRequest, e.StatusCode)
+}
+
+func (e *APIError) Unwrap() error {
+	return ErrAPIRequest
+}
+
+// DefaultBaseURL is the Google Pollen API forecast endpoint.
+const DefaultBaseURL = "https://pollen.googleapis.com/v1/forecast:lookup"
 
 // PollenClient fetches raw pollen forecast data.
 type PollenClient interface {
@@ -28,57 +65,150 @@ type HTTPClient interface {
 
 // GooglePollenClient implements PollenClient using the Google Pollen API.
 type GooglePollenClient struct {
-	apiKey     string
+	key        apikey.Source
 	httpClient HTTPClient
+	baseURL    string
+	language   string
+	log        *slog.Logger
 }
 
 // NewGooglePollenClient creates a new Google Pollen API client.
 func NewGooglePollenClient(apiKey string, client ...HTTPClient) *GooglePollenClient {
+	return NewGooglePollenClientWithSource(apikey.Static(apiKey), client...)
+}
+
+// NewGooglePollenClientWithSource creates a Google Pollen API client that
+// resolves its API key from src only when a request is made.
+func NewGooglePollenClientWithSource(src apikey.Source, client ...HTTPClient) *GooglePollenClient {
 	var c HTTPClient = http.DefaultClient
 	if len(client) > 0 && client[0] != nil {
 		c = client[0]
 	}
-	return &GooglePollenClient{apiKey: apiKey, httpClient: c}
+	return &GooglePollenClient{key: src, httpClient: c, baseURL: DefaultBaseURL, language: i18n.Default, log: logging.Discard}
+}
+
+// WithBaseURL points the client at another endpoint, such as an internal
+// gateway or a mock server. An empty u keeps the current one.
+func (c *GooglePollenClient) WithBaseURL(u string) *GooglePollenClient {
+	if u != "" {
+		c.baseURL = u
+	}
+	return c
+}
+
+// WithLanguage requests category names, plant descriptions, and health
+// recommendations in lang, a BCP 47 tag such as "ja" or "pt-BR". An empty
+// lang keeps the current one.
+func (c *GooglePollenClient) WithLanguage(lang string) *GooglePollenClient {
+	if lang != "" {
+		c.language = lang
+	}
+	return c
+}
+
+// WithLogger sets the logger for requests. A nil l keeps the current one.
+func (c *GooglePollenClient) WithLogger(l *slog.Logger) *GooglePollenClient {
+	if l != nil {
+		c.log = l
+	}
+	return c
 }
 
 // GetForecast fetches pollen forecast data from the Google Pollen API.
 func (c *GooglePollenClient) GetForecast(ctx context.Context, lat, lng float64, days int) (*RawForecastResponse, error) {
+	ctx, span := tracer.Start(ctx, "pollen.GetForecast", trace.WithAttributes(
+		attribute.Float64("pollenow.lat", lat),
+		attribute.Float64("pollenow.lng", lng),
+		attribute.Int("pollenow.days", days),
+	))
+	defer span.End()
+
+	raw, err := c.getForecast(ctx, lat, lng, days)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return raw, err
+}
+
+func (c *GooglePollenClient) getForecast(ctx context.Context, lat, lng float64, days int) (*RawForecastResponse, error) {
 	if days < 1 || days > 5 {
 		return nil, ErrInvalidDays
 	}
 
-	u := fmt.Sprintf(
-		"%s?key=%s&location.latitude=%f&location.longitude=%f&days=%d&plantsDescription=true&languageCode=en",
-		baseURL, c.apiKey, lat, lng, days,
-	)
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pollen base URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("location.latitude", strconv.FormatFloat(lat, 'f', 6, 64))
+	q.Set("location.longitude", strconv.FormatFloat(lng, 'f', 6, 64))
+	q.Set("days", strconv.Itoa(days))
+	q.Set("plantsDescription", "true")
+	q.Set("languageCode", c.language)
+	u.RawQuery = q.Encode()
+
+	resp, err := c.get(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var data RawForecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("parsing pollen response: %w", err)
+	}
+
+	return &data, nil
+}
+
+// get sends an authenticated GET to u. Any status other than 200 is
+// returned as an *APIError; otherwise the caller must close the body.
+func (c *GooglePollenClient) get(ctx context.Context, u string) (*http.Response, error) {
+	key, err := c.key.Key(ctx)
+	if err != nil {
+		if errors.Is(err, apikey.ErrNoKey) {
+			return nil, ErrNoAPIKey
+		}
+		return nil, err
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating pollen request: %w", err)
 	}
+	req.Header.Set(apikey.Header, key)
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		c.log.Info("api request", "api", "pollen", "url", httpx.RedactURL(req.URL), "latency", time.Since(start), "err", err)
 		return nil, fmt.Errorf("pollen request failed: %w", err)
 	}
-	defer resp.Body.Close()
+	c.log.Info("api request", "api", "pollen", "url", httpx.RedactURL(req.URL), "status", resp.StatusCode, "latency", time.Since(start))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
 	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
 		var errResp struct {
 			Error struct {
 				Message string `json:"message"`
+				Status  string `json:"status"`
+				Details []struct {
+					Reason string `json:"reason"`
+				} `json:"details"`
 			} `json:"error"`
 		}
-		if json.NewDecoder(resp.Body).Decode(&errResp) == nil && errResp.Error.Message != "" {
-			return nil, fmt.Errorf("%w: %s", ErrAPIRequest, errResp.Error.Message)
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if json.NewDecoder(resp.Body).Decode(&errResp) == nil {
+			apiErr.Status, apiErr.Message = errResp.Error.Status, errResp.Error.Message
+			for _, d := range errResp.Error.Details {
+				if d.Reason != "" {
+					apiErr.Reasons = append(apiErr.Reasons, d.Reason)
+				}
+			}
 		}
-		return nil, fmt.Errorf("%w: status %d", ErrAPIRequest, resp.StatusCode)
+		return nil, apiErr
 	}
-
-	var data RawForecastResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("parsing pollen response: %w", err)
-	}
-
-	return &data, nil
+	return resp, nil
 }
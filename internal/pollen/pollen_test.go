@@ -12,9 +12,11 @@ import (
 type mockHTTPClient struct {
 	response *http.Response
 	err      error
+	req      *http.Request // last request sent
 }
 
 func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.req = req
 	return m.response, m.err
 }
 
@@ -101,3 +103,81 @@ func TestGetForecastNetworkError(t *testing.T) {
 		t.Error("expected error for network failure")
 	}
 }
+
+func TestGetForecastBaseURL(t *testing.T) {
+	client := &mockHTTPClient{err: errors.New("stop")}
+
+	c := NewGooglePollenClient("test-key", client).WithBaseURL("http://gateway.internal:8080/v1/forecast:lookup?tenant=a%26b")
+	c.GetForecast(context.Background(), 37.44, -122.14, 1)
+
+	u := client.req.URL
+	if u.Host != "gateway.internal:8080" || u.Path != "/v1/forecast:lookup" {
+		t.Errorf("request went to %s%s", u.Host, u.Path)
+	}
+	q := u.Query()
+	if q.Get("days") != "1" || q.Get("location.latitude") != "37.440000" || q.Get("location.longitude") != "-122.140000" {
+		t.Errorf("query: got %q", u.RawQuery)
+	}
+	if q.Get("tenant") != "a&b" {
+		t.Errorf("base URL query lost: got %q", u.RawQuery)
+	}
+
+	NewGooglePollenClient("test-key", client).WithBaseURL("").GetForecast(context.Background(), 37.44, -122.14, 1)
+	if client.req.URL.Host != "pollen.googleapis.com" {
+		t.Errorf("empty base URL should keep the default, got %s", client.req.URL.Host)
+	}
+}
+
+func TestGetForecastLanguage(t *testing.T) {
+	client := &mockHTTPClient{err: errors.New("stop")}
+
+	NewGooglePollenClient("test-key", client).GetForecast(context.Background(), 37.44, -122.14, 1)
+	if got := client.req.URL.Query().Get("languageCode"); got != "en" {
+		t.Errorf("default languageCode: got %q", got)
+	}
+
+	NewGooglePollenClient("test-key", client).WithLanguage("pt-BR").GetForecast(context.Background(), 37.44, -122.14, 1)
+	if got := client.req.URL.Query().Get("languageCode"); got != "pt-BR" {
+		t.Errorf("languageCode: got %q", got)
+	}
+}
+
+func TestGetForecastKeyHeader(t *testing.T) {
+	client := &mockHTTPClient{err: errors.New("stop")}
+	NewGooglePollenClient("test-key", client).GetForecast(context.Background(), 37.44, -122.14, 1)
+
+	if got := client.req.Header.Get("X-Goog-Api-Key"); got != "test-key" {
+		t.Errorf("X-Goog-Api-Key: got %q", got)
+	}
+	if strings.Contains(client.req.URL.String(), "test-key") {
+		t.Errorf("key leaked into the URL: %s", client.req.URL)
+	}
+}
+
+func TestHeatmapTile(t *testing.T) {
+	client := &mockHTTPClient{
+		response: &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("\x89PNG")),
+		},
+	}
+
+	c := NewGooglePollenClient("test-key", client).WithBaseURL("http://gateway.internal/pollen/v1/forecast:lookup")
+	png, err := c.HeatmapTile(context.Background(), MapTypeTree, 3, 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(png) != "\x89PNG" {
+		t.Errorf("body: got %q", png)
+	}
+	if got := client.req.URL.String(); got != "http://gateway.internal/pollen/v1/mapTypes/TREE_UPI/heatmapTiles/3/1/2" {
+		t.Errorf("URL: got %s", got)
+	}
+
+	if _, err := c.HeatmapTile(context.Background(), MapTypeTree, 3, 8, 0); !errors.Is(err, ErrInvalidTile) {
+		t.Errorf("expected ErrInvalidTile for x outside the grid, got %v", err)
+	}
+	if _, err := MapType("cactus"); !errors.Is(err, ErrUnknownMapType) {
+		t.Errorf("expected ErrUnknownMapType, got %v", err)
+	}
+}
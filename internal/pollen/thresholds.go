@@ -0,0 +1,103 @@
+package pollen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidThresholds = errors.New("invalid thresholds")
+)
+
+// Thresholds decide when a Universal Pollen Index value counts as high or
+// low. The validate and desc tags describe the thresholds config section.
+type Thresholds struct {
+	High int `json:"high" yaml:"high" validate:"upi" desc:"Values at or above are high (1-5)"`
+	Low  int `json:"low" yaml:"low" validate:"upi" desc:"Values at or below are low (0-5)"`
+}
+
+// DefaultThresholds matches the UPI categories: 4 is "High", 2 is "Low".
+var DefaultThresholds = Thresholds{High: 4, Low: 2}
+
+// Validate reports whether the thresholds are usable: both within the
+// index and low below high.
+func (t Thresholds) Validate() error {
+	if t.Low < 0 || t.High > 5 || t.Low >= t.High {
+		return fmt.Errorf("%w: need 0 <= low < high <= 5, got low %d high %d", ErrInvalidThresholds, t.Low, t.High)
+	}
+	return nil
+}
+
+// TypeLevel pairs a pollen type with its level for one day.
+type TypeLevel struct {
+	Code  string // "GRASS", "TREE", "WEED"
+	Name  string // "Grass", "Tree", "Weed"
+	Level PollenLevel
+}
+
+// Types returns the day's grass, tree, and weed levels in display order.
+func (d DayForecast) Types() []TypeLevel {
+	return []TypeLevel{
+		{Code: "GRASS", Name: "Grass", Level: d.Grass},
+		{Code: "TREE", Name: "Tree", Level: d.Tree},
+		{Code: "WEED", Name: "Weed", Level: d.Weed},
+	}
+}
+
+// IsHigh reports whether the level is at or above the high threshold.
+func (t Thresholds) IsHigh(l PollenLevel) bool {
+	return l.Level != nil && *l.Level >= t.High
+}
+
+// IsLow reports whether the level is at or below the low threshold.
+// Missing data counts as low.
+func (t Thresholds) IsLow(l PollenLevel) bool {
+	return l.Level == nil || *l.Level <= t.Low
+}
+
+// Alert returns the highest pollen type at or above the high threshold.
+// Ties go to the type listed first.
+func (t Thresholds) Alert(d DayForecast) (TypeLevel, bool) {
+	var best TypeLevel
+	found := false
+	for _, tl := range d.Types() {
+		if !t.IsHigh(tl.Level) {
+			continue
+		}
+		if !found || *tl.Level.Level > *best.Level.Level {
+			best = tl
+			found = true
+		}
+	}
+	return best, found
+}
+
+// AllLow reports whether every pollen type is at or below the low threshold.
+func (t Thresholds) AllLow(d DayForecast) bool {
+	for _, tl := range d.Types() {
+		if !t.IsLow(tl.Level) {
+			return false
+		}
+	}
+	return true
+}
+
+// HighTargets returns the targets that are high on the given day. A target
+// is a pollen type ("tree") or a plant code or name ("oak", "Birch").
+func (t Thresholds) HighTargets(d DayForecast, targets []string) []TypeLevel {
+	var out []TypeLevel
+	for _, target := range targets {
+		for _, tl := range d.Types() {
+			if strings.EqualFold(target, tl.Code) && t.IsHigh(tl.Level) {
+				out = append(out, tl)
+			}
+		}
+		for _, p := range d.Plants {
+			if (strings.EqualFold(target, p.Code) || strings.EqualFold(target, p.DisplayName)) && t.IsHigh(p.PollenLevel) {
+				out = append(out, TypeLevel{Code: p.Code, Name: p.DisplayName, Level: p.PollenLevel})
+			}
+		}
+	}
+	return out
+}
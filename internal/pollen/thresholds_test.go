@@ -0,0 +1,69 @@
+package pollen
+
+import "testing"
+
+func lvl(v int, cat string) PollenLevel {
+	return PollenLevel{Level: &v, Category: cat}
+}
+
+func TestThresholdsAlert(t *testing.T) {
+	th := DefaultThresholds
+
+	day := DayForecast{Grass: lvl(4, "High"), Tree: lvl(5, "Very High"), Weed: lvl(1, "Very Low")}
+	alert, ok := th.Alert(day)
+	if !ok || alert.Name != "Tree" {
+		t.Errorf("Alert: got %q (%v), want Tree", alert.Name, ok)
+	}
+
+	day = DayForecast{Grass: lvl(4, "High"), Tree: lvl(4, "High")}
+	if alert, _ := th.Alert(day); alert.Name != "Grass" {
+		t.Errorf("Alert tie: got %q, want Grass", alert.Name)
+	}
+
+	day = DayForecast{Grass: lvl(3, "Moderate"), Tree: lvl(2, "Low"), Weed: PollenLevel{Category: "No Data"}}
+	if _, ok := th.Alert(day); ok {
+		t.Error("Alert should not fire below the high threshold")
+	}
+	if th.AllLow(day) {
+		t.Error("AllLow should be false with a Moderate type")
+	}
+
+	day.Grass = lvl(2, "Low")
+	if !th.AllLow(day) {
+		t.Error("AllLow should be true when every type is at or below Low")
+	}
+}
+
+func TestThresholdsHighTargets(t *testing.T) {
+	day := DayForecast{
+		Tree:  lvl(4, "High"),
+		Grass: lvl(1, "Very Low"),
+		Plants: []PlantLevel{
+			{Code: "BIRCH", DisplayName: "Birch", PollenLevel: lvl(5, "Very High")},
+			{Code: "OAK", DisplayName: "Oak", PollenLevel: lvl(2, "Low")},
+		},
+	}
+
+	got := DefaultThresholds.HighTargets(day, []string{"tree", "grass", "birch", "Oak"})
+	if len(got) != 2 || got[0].Name != "Tree" || got[1].Name != "Birch" {
+		t.Errorf("HighTargets: got %+v, want [Tree Birch]", got)
+	}
+
+	custom := Thresholds{High: 2, Low: 1}
+	if got := custom.HighTargets(day, []string{"oak"}); len(got) != 1 {
+		t.Errorf("custom threshold: got %d targets, want 1", len(got))
+	}
+}
+
+func TestThresholdsValidate(t *testing.T) {
+	for _, th := range []Thresholds{DefaultThresholds, {High: 1, Low: 0}, {High: 5, Low: 4}} {
+		if err := th.Validate(); err != nil {
+			t.Errorf("%+v: %v", th, err)
+		}
+	}
+	for _, th := range []Thresholds{{High: 2, Low: 2}, {High: 6, Low: 2}, {High: 3, Low: -1}} {
+		if err := th.Validate(); err == nil {
+			t.Errorf("%+v: expected an error", th)
+		}
+	}
+}
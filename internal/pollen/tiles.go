@@ -0,0 +1,89 @@
+package pollen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	ErrUnknownMapType = errors.New("unknown heatmap type")
+	ErrInvalidTile    = errors.New("invalid heatmap tile")
+)
+
+// Heatmap types served by the Pollen API.
+const (
+	MapTypeTree  = "TREE_UPI"
+	MapTypeGrass = "GRASS_UPI"
+	MapTypeWeed  = "WEED_UPI"
+)
+
+// MaxZoom is the deepest zoom level the Pollen API serves tiles for.
+const MaxZoom = 16
+
+// MapType returns the heatmap type for a pollen type: tree, grass, or weed.
+func MapType(plant string) (string, error) {
+	switch plant {
+	case "tree":
+		return MapTypeTree, nil
+	case "grass":
+		return MapTypeGrass, nil
+	case "weed":
+		return MapTypeWeed, nil
+	}
+	return "", fmt.Errorf("%w %q (use tree, grass, or weed)", ErrUnknownMapType, plant)
+}
+
+// HeatmapTile fetches one 256x256 PNG heatmap tile in the standard web map
+// tile grid. Tiles are mostly transparent, colored where pollen is present.
+func (c *GooglePollenClient) HeatmapTile(ctx context.Context, mapType string, zoom, x, y int) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "pollen.HeatmapTile", trace.WithAttributes(
+		attribute.String("pollenow.map_type", mapType),
+		attribute.Int("pollenow.zoom", zoom),
+		attribute.Int("pollenow.tile.x", x),
+		attribute.Int("pollenow.tile.y", y),
+	))
+	defer span.End()
+
+	png, err := c.heatmapTile(ctx, mapType, zoom, x, y)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return png, err
+}
+
+func (c *GooglePollenClient) heatmapTile(ctx context.Context, mapType string, zoom, x, y int) ([]byte, error) {
+	if zoom < 0 || zoom > MaxZoom {
+		return nil, fmt.Errorf("%w: zoom must be between 0 and %d", ErrInvalidTile, MaxZoom)
+	}
+	if n := 1 << zoom; x < 0 || x >= n || y < 0 || y >= n {
+		return nil, fmt.Errorf("%w: %d/%d/%d is outside the grid", ErrInvalidTile, zoom, x, y)
+	}
+
+	// Tiles live beside forecast:lookup, so a gateway or mock base URL
+	// covers both.
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pollen base URL: %w", err)
+	}
+	ref := &url.URL{Path: fmt.Sprintf("mapTypes/%s/heatmapTiles/%d/%d/%d", mapType, zoom, x, y)}
+
+	resp, err := c.get(ctx, base.ResolveReference(ref).String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading heatmap tile: %w", err)
+	}
+	return data, nil
+}
@@ -1,5 +1,10 @@
 package pollen
 
+import (
+	"fmt"
+	"math"
+)
+
 // --- Raw API response types (mirror Google Pollen API JSON) ---
 
 // RawForecastResponse is the top-level response from the Google Pollen API.
@@ -48,6 +53,16 @@ type Color struct {
 	Blue  float64 `json:"blue,omitempty"`
 }
 
+// Hex returns c as "#rrggbb".
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", channel(c.Red), channel(c.Green), channel(c.Blue))
+}
+
+// channel scales a 0.0-1.0 color component to 0-255.
+func channel(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
 // PlantInfo represents data for a specific plant species.
 type PlantInfo struct {
 	Code        string     `json:"code"`
@@ -63,16 +78,36 @@ type PollenLevel struct {
 	Level    *int   `json:"level"`    // nil means no data
 	Category string `json:"category"` // "None", "Very Low", "Low", "Moderate", "High", "Very High", "No Data"
 	InSeason bool   `json:"inSeason"`
+	Color    string `json:"color,omitempty"` // the API's color for the level, "#rrggbb"
+}
+
+// PlantLevel represents the formatted pollen level for one plant species.
+type PlantLevel struct {
+	Code        string `json:"code"`        // "OAK", "BIRCH", "RAGWEED"
+	DisplayName string `json:"displayName"` // "Oak"
+	PollenLevel
 }
 
 // DayForecast represents the formatted forecast for a single day.
 type DayForecast struct {
-	Date                  string      `json:"date"`    // "2025-06-15"
-	DayName               string      `json:"dayName"` // "Today", "Tomorrow", "Wednesday"
-	Grass                 PollenLevel `json:"grass"`
-	Tree                  PollenLevel `json:"tree"`
-	Weed                  PollenLevel `json:"weed"`
-	HealthRecommendations []string    `json:"healthRecommendations"`
+	Date                  string       `json:"date"`    // "2025-06-15"
+	DayName               string       `json:"dayName"` // "Today", "Tomorrow", "Wednesday"
+	Grass                 PollenLevel  `json:"grass"`
+	Tree                  PollenLevel  `json:"tree"`
+	Weed                  PollenLevel  `json:"weed"`
+	Plants                []PlantLevel `json:"plants,omitempty"`
+	HealthRecommendations []string     `json:"healthRecommendations"`
+}
+
+// InSeasonPlants returns the display names of plants that are in season.
+func (d DayForecast) InSeasonPlants() []string {
+	var names []string
+	for _, p := range d.Plants {
+		if p.InSeason {
+			names = append(names, p.DisplayName)
+		}
+	}
+	return names
 }
 
 // Forecast is the fully formatted forecast result.
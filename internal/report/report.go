@@ -0,0 +1,231 @@
+package report
+
+import (
+	"embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/shunito/pollenow/internal/history"
+	"github.com/shunito/pollenow/internal/journal"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown report format")
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+// Format selects the report output format.
+type Format string
+
+const (
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "md"
+	FormatCSV      Format = "csv"
+)
+
+// ParseFormat converts a user-supplied format name into a Format.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "html":
+		return FormatHTML, nil
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "csv":
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("%w %q — valid formats: html, md, csv", ErrUnknownFormat, s)
+	}
+}
+
+// Day is one row of the report.
+type Day struct {
+	Date        string
+	Forecast    *pollen.DayForecast // nil when no pollen data was recorded
+	Plants      []string            // in-season plant names
+	Symptoms    []journal.Entry
+	Medications []journal.Entry
+}
+
+// Summary holds the headline numbers shown above the daily table.
+type Summary struct {
+	DaysWithData    int
+	HighPollenDays  int
+	SymptomDays     int
+	MaxSeverity     int
+	AverageSeverity float64
+	MedicationDoses int
+}
+
+// Report combines pollen history and journal entries for a date range.
+type Report struct {
+	ZIP         string
+	Location    string
+	From        string
+	To          string
+	GeneratedAt time.Time
+	Days        []Day
+	Summary     Summary
+}
+
+// Build assembles a report covering every date in [from, to]. Days with a
+// pollen type at or above th.High count as high pollen days.
+func Build(zip string, records []history.Record, entries []journal.Entry, from, to time.Time, th pollen.Thresholds) *Report {
+	r := &Report{
+		ZIP:         zip,
+		Location:    zip,
+		From:        from.Format(pollen.DateLayout),
+		To:          to.Format(pollen.DateLayout),
+		GeneratedAt: time.Now(),
+	}
+
+	byDate := make(map[string]*pollen.DayForecast, len(records))
+	for i := range records {
+		byDate[records[i].Day.Date] = &records[i].Day
+		if records[i].Location.DisplayName != "" {
+			r.Location = records[i].Location.DisplayName
+		}
+	}
+
+	severitySum, severityCount := 0, 0
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := Day{Date: d.Format(pollen.DateLayout)}
+		if fc, ok := byDate[day.Date]; ok {
+			day.Forecast = fc
+			day.Plants = fc.InSeasonPlants()
+			r.Summary.DaysWithData++
+			if _, high := th.Alert(*fc); high {
+				r.Summary.HighPollenDays++
+			}
+		}
+
+		for _, e := range entries {
+			if e.Date != day.Date {
+				continue
+			}
+			switch e.Kind {
+			case journal.KindSymptom:
+				day.Symptoms = append(day.Symptoms, e)
+				severitySum += e.Severity
+				severityCount++
+				if e.Severity > r.Summary.MaxSeverity {
+					r.Summary.MaxSeverity = e.Severity
+				}
+			case journal.KindMedication:
+				day.Medications = append(day.Medications, e)
+				r.Summary.MedicationDoses++
+			}
+		}
+		if len(day.Symptoms) > 0 {
+			r.Summary.SymptomDays++
+		}
+
+		r.Days = append(r.Days, day)
+	}
+
+	if severityCount > 0 {
+		r.Summary.AverageSeverity = float64(severitySum) / float64(severityCount)
+	}
+
+	return r
+}
+
+// Render writes the report to w in the given format.
+func (r *Report) Render(w io.Writer, format Format) error {
+	switch format {
+	case FormatHTML:
+		t, err := htmltemplate.New("report.html.tmpl").Funcs(templateFuncs).ParseFS(templateFS, "templates/report.html.tmpl")
+		if err != nil {
+			return fmt.Errorf("parsing html template: %w", err)
+		}
+		return t.Execute(w, r)
+	case FormatMarkdown:
+		t, err := texttemplate.New("report.md.tmpl").Funcs(templateFuncs).ParseFS(templateFS, "templates/report.md.tmpl")
+		if err != nil {
+			return fmt.Errorf("parsing markdown template: %w", err)
+		}
+		return t.Execute(w, r)
+	case FormatCSV:
+		return r.renderCSV(w)
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+func (r *Report) renderCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{
+		"date", "grass_level", "grass_category", "tree_level", "tree_category",
+		"weed_level", "weed_category", "in_season_plants", "symptoms", "medications",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, d := range r.Days {
+		row := []string{d.Date, "", "", "", "", "", "", "", "", ""}
+		if d.Forecast != nil {
+			row[1], row[2] = csvLevel(d.Forecast.Grass)
+			row[3], row[4] = csvLevel(d.Forecast.Tree)
+			row[5], row[6] = csvLevel(d.Forecast.Weed)
+		}
+		row[7] = strings.Join(d.Plants, "; ")
+		row[8] = joinEntries(d.Symptoms)
+		row[9] = joinEntries(d.Medications)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+var templateFuncs = map[string]any{
+	"level":   levelText,
+	"entries": joinEntries,
+	"join":    strings.Join,
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04")
+	},
+	"severity": func(f float64) string {
+		return strconv.FormatFloat(f, 'f', 1, 64)
+	},
+}
+
+// levelText formats a pollen level as "4 High", or "-" without data.
+func levelText(l pollen.PollenLevel) string {
+	if l.Level == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d %s", *l.Level, l.Category)
+}
+
+func csvLevel(l pollen.PollenLevel) (string, string) {
+	if l.Level == nil {
+		return "", l.Category
+	}
+	return strconv.Itoa(*l.Level), l.Category
+}
+
+// joinEntries formats journal entries as "sneezing (3), itchy eyes (2)".
+func joinEntries(entries []journal.Entry) string {
+	parts := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if e.Severity > 0 {
+			parts = append(parts, fmt.Sprintf("%s (%d)", e.Name, e.Severity))
+		} else {
+			parts = append(parts, e.Name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
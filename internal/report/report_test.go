@@ -0,0 +1,126 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/history"
+	"github.com/shunito/pollenow/internal/journal"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+func sampleReport() *Report {
+	high, low := 4, 1
+	records := []history.Record{
+		{
+			ZIP:      "94025",
+			Location: geocoding.Location{DisplayName: "Menlo Park, CA 94025, USA"},
+			Day: pollen.DayForecast{
+				Date:  "2025-06-15",
+				Grass: pollen.PollenLevel{Level: &low, Category: "Very Low"},
+				Tree:  pollen.PollenLevel{Level: &high, Category: "High", InSeason: true},
+				Weed:  pollen.PollenLevel{Category: "No Data"},
+				Plants: []pollen.PlantLevel{
+					{Code: "OAK", DisplayName: "Oak", PollenLevel: pollen.PollenLevel{Level: &high, Category: "High", InSeason: true}},
+				},
+			},
+		},
+	}
+	entries := []journal.Entry{
+		{Date: "2025-06-15", Kind: journal.KindSymptom, Name: "sneezing", Severity: 4},
+		{Date: "2025-06-16", Kind: journal.KindSymptom, Name: "itchy <eyes>", Severity: 2},
+		{Date: "2025-06-16", Kind: journal.KindMedication, Name: "cetirizine"},
+	}
+
+	from := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 17, 0, 0, 0, 0, time.UTC)
+	return Build("94025", records, entries, from, to, pollen.DefaultThresholds)
+}
+
+func TestBuild(t *testing.T) {
+	r := sampleReport()
+
+	if r.Location != "Menlo Park, CA 94025, USA" {
+		t.Errorf("Location: got %q", r.Location)
+	}
+	if len(r.Days) != 3 {
+		t.Fatalf("Days: got %d, want 3", len(r.Days))
+	}
+	if r.Days[0].Forecast == nil || r.Days[1].Forecast != nil {
+		t.Error("only the first day should have pollen data")
+	}
+	if len(r.Days[0].Plants) != 1 || r.Days[0].Plants[0] != "Oak" {
+		t.Errorf("Plants: got %v, want [Oak]", r.Days[0].Plants)
+	}
+
+	s := r.Summary
+	if s.DaysWithData != 1 || s.HighPollenDays != 1 {
+		t.Errorf("pollen summary: got %d days / %d high, want 1 / 1", s.DaysWithData, s.HighPollenDays)
+	}
+	if s.SymptomDays != 2 || s.MaxSeverity != 4 || s.AverageSeverity != 3 {
+		t.Errorf("symptom summary: got %+v", s)
+	}
+	if s.MedicationDoses != 1 {
+		t.Errorf("MedicationDoses: got %d, want 1", s.MedicationDoses)
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().Render(&buf, FormatHTML); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"Menlo Park, CA 94025, USA", "4 High", "Oak", "sneezing (4)", "itchy &lt;eyes&gt; (2)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML output missing %q", want)
+		}
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().Render(&buf, FormatMarkdown); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "| 2025-06-15 | 1 Very Low | 4 High | - | Oak | sneezing (4) |  |") {
+		t.Errorf("unexpected markdown row:\n%s", buf.String())
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().Render(&buf, FormatCSV); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("got %d rows, want 4 (header + 3 days)", len(rows))
+	}
+	if rows[1][3] != "4" || rows[1][4] != "High" {
+		t.Errorf("tree columns: got %q %q", rows[1][3], rows[1][4])
+	}
+	if rows[2][9] != "cetirizine" {
+		t.Errorf("medications: got %q", rows[2][9])
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("Markdown"); err != nil || f != FormatMarkdown {
+		t.Errorf("ParseFormat(Markdown): got %q, %v", f, err)
+	}
+	if _, err := ParseFormat("pdf"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+package season
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shunito/pollenow/internal/history"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+// DefaultMaxGap is the number of days without an in-season reading that can
+// pass before a season is considered over. It bridges missing history and
+// single off-season days inside a season.
+const DefaultMaxGap = 7
+
+// Kind distinguishes pollen types from individual plant species.
+type Kind string
+
+const (
+	KindType  Kind = "type"
+	KindPlant Kind = "plant"
+)
+
+// Season is one continuous in-season period for a pollen type or plant.
+type Season struct {
+	Code      string // "TREE", "BIRCH"
+	Name      string // "Tree", "Birch"
+	Kind      Kind
+	Year      int
+	Start     time.Time
+	End       time.Time // last in-season day on record
+	Ongoing   bool      // in season on the most recent recorded day
+	PeakLevel int       // -1 when no level was reported
+	PeakDate  time.Time
+}
+
+// Days returns the length of the season in days, inclusive.
+func (s Season) Days() int {
+	return int(s.End.Sub(s.Start).Hours()/24) + 1
+}
+
+// Covers reports whether date falls within the season.
+func (s Season) Covers(date time.Time) bool {
+	return !date.Before(s.Start) && !date.After(s.End)
+}
+
+type reading struct {
+	date     time.Time
+	name     string
+	kind     Kind
+	inSeason bool
+	level    *int
+}
+
+// Detect finds in-season periods for every pollen type and plant in the
+// history. Periods separated by at most maxGap days are merged, and a
+// period never spans a new year. Seasons are sorted by kind, code, then
+// start date.
+func Detect(records []history.Record, maxGap int) []Season {
+	byCode := make(map[string][]reading)
+	var last time.Time
+
+	for _, r := range records {
+		date, err := time.Parse(pollen.DateLayout, r.Day.Date)
+		if err != nil {
+			continue
+		}
+		if date.After(last) {
+			last = date
+		}
+		for _, tl := range r.Day.Types() {
+			byCode[tl.Code] = append(byCode[tl.Code], reading{date, tl.Name, KindType, tl.Level.InSeason, tl.Level.Level})
+		}
+		for _, p := range r.Day.Plants {
+			byCode[p.Code] = append(byCode[p.Code], reading{date, p.DisplayName, KindPlant, p.InSeason, p.Level})
+		}
+	}
+
+	var seasons []Season
+	for code, readings := range byCode {
+		sort.Slice(readings, func(i, j int) bool { return readings[i].date.Before(readings[j].date) })
+
+		var cur *Season
+		flush := func() {
+			if cur != nil {
+				cur.Ongoing = cur.End.Equal(last)
+				seasons = append(seasons, *cur)
+				cur = nil
+			}
+		}
+
+		for _, rd := range readings {
+			if !rd.inSeason {
+				continue
+			}
+			gap := 0
+			if cur != nil {
+				gap = int(rd.date.Sub(cur.End).Hours()/24) - 1
+			}
+			if cur != nil && (gap > maxGap || rd.date.Year() != cur.Year) {
+				flush()
+			}
+			if cur == nil {
+				cur = &Season{Code: code, Name: rd.name, Kind: rd.kind, Year: rd.date.Year(), Start: rd.date, PeakLevel: -1}
+			}
+			cur.End = rd.date
+			if rd.level != nil && *rd.level > cur.PeakLevel {
+				cur.PeakLevel = *rd.level
+				cur.PeakDate = rd.date
+			}
+		}
+		flush()
+	}
+
+	sort.Slice(seasons, func(i, j int) bool {
+		a, b := seasons[i], seasons[j]
+		if a.Kind != b.Kind {
+			return a.Kind == KindType
+		}
+		if oa, ob := typeOrder(a.Code), typeOrder(b.Code); oa != ob {
+			return oa < ob
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return a.Start.Before(b.Start)
+	})
+
+	return seasons
+}
+
+// typeOrder keeps grass, tree, weed in the same order as the forecast
+// table; plants sort after them.
+func typeOrder(code string) int {
+	types := pollen.DayForecast{}.Types()
+	for i, tl := range types {
+		if tl.Code == code {
+			return i
+		}
+	}
+	return len(types)
+}
@@ -0,0 +1,102 @@
+package season
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shunito/pollenow/internal/history"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+func date(y, m, d int) time.Time {
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+}
+
+// days builds one record per day from start to end with the tree and birch
+// in-season flag set by inSeason.
+func days(start, end time.Time, inSeason func(time.Time) bool, level int) []history.Record {
+	var records []history.Record
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		v := level
+		on := inSeason(d)
+		records = append(records, history.Record{Day: pollen.DayForecast{
+			Date: d.Format(pollen.DateLayout),
+			Tree: pollen.PollenLevel{Level: &v, InSeason: on},
+			Plants: []pollen.PlantLevel{
+				{Code: "BIRCH", DisplayName: "Birch", PollenLevel: pollen.PollenLevel{InSeason: on}},
+			},
+		}})
+	}
+	return records
+}
+
+func TestDetect(t *testing.T) {
+	// 2024: Mar 1 - Apr 30 with a 3-day gap in the middle that should be bridged.
+	records := days(date(2024, 2, 15), date(2024, 5, 15), func(d time.Time) bool {
+		if d.After(date(2024, 3, 20)) && d.Before(date(2024, 3, 24)) {
+			return false
+		}
+		return !d.Before(date(2024, 3, 1)) && !d.After(date(2024, 4, 30))
+	}, 3)
+	// 2025: in season from Mar 10 through the last recorded day.
+	records = append(records, days(date(2025, 3, 1), date(2025, 3, 31), func(d time.Time) bool {
+		return !d.Before(date(2025, 3, 10))
+	}, 4)...)
+
+	seasons := Detect(records, DefaultMaxGap)
+
+	var tree []Season
+	for _, s := range seasons {
+		if s.Code == "TREE" {
+			tree = append(tree, s)
+		}
+	}
+	if len(tree) != 2 {
+		t.Fatalf("got %d tree seasons, want 2: %+v", len(tree), tree)
+	}
+
+	if !tree[0].Start.Equal(date(2024, 3, 1)) || !tree[0].End.Equal(date(2024, 4, 30)) {
+		t.Errorf("2024 season: got %s - %s", tree[0].Start.Format(pollen.DateLayout), tree[0].End.Format(pollen.DateLayout))
+	}
+	if tree[0].Ongoing {
+		t.Error("2024 season should not be ongoing")
+	}
+	if tree[0].Days() != 61 {
+		t.Errorf("2024 season length: got %d, want 61", tree[0].Days())
+	}
+
+	if !tree[1].Start.Equal(date(2025, 3, 10)) || !tree[1].Ongoing {
+		t.Errorf("2025 season: got start %s ongoing %v", tree[1].Start.Format(pollen.DateLayout), tree[1].Ongoing)
+	}
+	if tree[1].PeakLevel != 4 {
+		t.Errorf("2025 peak: got %d, want 4", tree[1].PeakLevel)
+	}
+
+	// Types sort before plants.
+	if seasons[0].Kind != KindType || seasons[len(seasons)-1].Kind != KindPlant {
+		t.Error("seasons should list pollen types before plants")
+	}
+	birch := seasons[len(seasons)-1]
+	if birch.Name != "Birch" || birch.PeakLevel != -1 {
+		t.Errorf("birch season: got %+v", birch)
+	}
+}
+
+func TestDetectSplitsLongGap(t *testing.T) {
+	// Spring and autumn grass seasons must stay separate.
+	records := days(date(2024, 4, 1), date(2024, 10, 31), func(d time.Time) bool {
+		m := d.Month()
+		return m == time.May || m == time.September
+	}, 2)
+
+	seasons := Detect(records, DefaultMaxGap)
+	count := 0
+	for _, s := range seasons {
+		if s.Code == "TREE" {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("got %d seasons, want 2", count)
+	}
+}
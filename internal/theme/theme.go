@@ -0,0 +1,143 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUnknownTheme = errors.New("unknown theme")
+)
+
+// Default is the theme used when none is configured.
+const Default = "default"
+
+// Color modes for --color and output.color.
+const (
+	ColorAuto   = "auto"   // color on a terminal unless NO_COLOR is set
+	ColorAlways = "always" // color even when piped or NO_COLOR is set
+	ColorNever  = "never"  // plain text
+)
+
+// ValidColorMode reports whether mode is auto, always, or never.
+func ValidColorMode(mode string) bool {
+	switch mode {
+	case ColorAuto, ColorAlways, ColorNever:
+		return true
+	}
+	return false
+}
+
+// Theme is a palette of hex colors, "#rrggbb". An empty color leaves the
+// terminal's own foreground.
+type Theme struct {
+	Name string
+
+	// Levels color Universal Pollen Index values 0 (None) through 5
+	// (Very High). NoData is for types without an index value.
+	Levels [6]string
+	NoData string
+
+	Title   string // headings
+	Header  string // table headers and section labels
+	Text    string // location lines and recommendations
+	Faint   string // legends, cache notes, and empty chart cells
+	Border  string // table borders
+	Good    string // all-clear messages and passed checks
+	Warning string // alerts and warnings
+	Error   string // errors and failed checks
+	Accent  string // markers such as "this week"
+}
+
+// themes are the built-in palettes by name.
+var themes = map[string]Theme{
+	Default: {
+		Levels:  [6]string{"#6b7280", "#10b981", "#10b981", "#f59e0b", "#ef4444", "#dc2626"},
+		NoData:  "#6b7280",
+		Title:   "#4361ee",
+		Header:  "#e5e7eb",
+		Text:    "#9ca3af",
+		Faint:   "#6b7280",
+		Border:  "#4b5563",
+		Good:    "#10b981",
+		Warning: "#f59e0b",
+		Error:   "#ef4444",
+		Accent:  "#4361ee",
+	},
+	// light darkens every color to read on a white background.
+	"light": {
+		Levels:  [6]string{"#6b7280", "#047857", "#047857", "#b45309", "#b91c1c", "#7f1d1d"},
+		NoData:  "#6b7280",
+		Title:   "#1d4ed8",
+		Header:  "#111827",
+		Text:    "#374151",
+		Faint:   "#6b7280",
+		Border:  "#9ca3af",
+		Good:    "#047857",
+		Warning: "#b45309",
+		Error:   "#b91c1c",
+		Accent:  "#1d4ed8",
+	},
+	// high-contrast uses saturated colors and white text on dark terminals.
+	"high-contrast": {
+		Levels:  [6]string{"#d1d5db", "#00ff00", "#00ff00", "#ffff00", "#ff0000", "#ff00ff"},
+		NoData:  "#d1d5db",
+		Title:   "#00ffff",
+		Header:  "#ffffff",
+		Text:    "#ffffff",
+		Faint:   "#d1d5db",
+		Border:  "#ffffff",
+		Good:    "#00ff00",
+		Warning: "#ffff00",
+		Error:   "#ff0000",
+		Accent:  "#00ffff",
+	},
+	// deuteranopia runs from blue to vermilion with the Okabe–Ito colors,
+	// which stay distinct without red–green discrimination.
+	"deuteranopia": {
+		Levels:  [6]string{"#999999", "#56b4e9", "#0072b2", "#f0e442", "#e69f00", "#d55e00"},
+		NoData:  "#999999",
+		Title:   "#56b4e9",
+		Header:  "#e5e7eb",
+		Text:    "#9ca3af",
+		Faint:   "#6b7280",
+		Border:  "#4b5563",
+		Good:    "#0072b2",
+		Warning: "#e69f00",
+		Error:   "#d55e00",
+		Accent:  "#56b4e9",
+	},
+	// monochrome has no colors; levels are told apart by their text.
+	"monochrome": {},
+}
+
+// Names lists the built-in themes, sorted.
+func Names() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Valid reports whether name is a built-in theme.
+func Valid(name string) bool {
+	_, ok := themes[name]
+	return ok
+}
+
+// Get returns the named theme. An empty name is the default theme.
+func Get(name string) (Theme, error) {
+	if name == "" {
+		name = Default
+	}
+	t, ok := themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("%w %q (choose from %s)", ErrUnknownTheme, name, strings.Join(Names(), ", "))
+	}
+	t.Name = name
+	return t, nil
+}
@@ -0,0 +1,48 @@
+package theme
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+var hexRegex = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+func TestThemesUseHexColors(t *testing.T) {
+	for _, name := range Names() {
+		th, err := Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		colors := append(th.Levels[:], th.NoData, th.Title, th.Header, th.Text, th.Faint, th.Border, th.Good, th.Warning, th.Error, th.Accent)
+		for _, c := range colors {
+			if c != "" && !hexRegex.MatchString(c) {
+				t.Errorf("%s: %q is not a #rrggbb color", name, c)
+			}
+		}
+		if name != "monochrome" && th.Levels[5] == "" {
+			t.Errorf("%s: no color for Very High", name)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	th, err := Get("")
+	if err != nil || th.Name != Default {
+		t.Errorf("empty name: got %q, %v", th.Name, err)
+	}
+	if _, err := Get("solarized"); !errors.Is(err, ErrUnknownTheme) {
+		t.Errorf("expected ErrUnknownTheme, got %v", err)
+	}
+}
+
+func TestValidColorMode(t *testing.T) {
+	for _, mode := range []string{"auto", "always", "never"} {
+		if !ValidColorMode(mode) {
+			t.Errorf("ValidColorMode(%q) = false", mode)
+		}
+	}
+	if ValidColorMode("yes") {
+		t.Error(`ValidColorMode("yes") = true`)
+	}
+}
@@ -0,0 +1,62 @@
+package timezone
+
+// Boundaries between the contiguous US zones, traced north to south from
+// 50°N to 24°N as longitude, latitude pairs. They follow county lines
+// closely enough for forecasts by ZIP code, not for surveying.
+var (
+	// eastCentral runs down Lake Michigan, around northwestern and
+	// southwestern Indiana, across Kentucky and Tennessee, down the
+	// Alabama–Georgia line, and along the Apalachicola River.
+	eastCentral = []point{
+		{-89.0, 50.0}, {-88.4, 47.5}, {-88.1, 46.0}, {-87.6, 45.1},
+		{-87.0, 43.0}, {-86.7, 41.77}, {-86.5, 41.0}, {-87.53, 40.75},
+		{-87.53, 38.5}, {-87.0, 38.2}, {-86.5, 38.1}, {-86.4, 38.0},
+		{-86.0, 37.6}, {-85.7, 37.4}, {-85.2, 37.1}, {-84.95, 36.6},
+		{-84.75, 36.2}, {-84.9, 35.8}, {-85.15, 35.4}, {-85.45, 35.0},
+		{-85.6, 34.98}, {-85.35, 33.5}, {-85.0, 32.3}, {-85.05, 31.0},
+		{-84.85, 30.7}, {-85.2, 29.6}, {-85.2, 24.0},
+	}
+
+	// centralMountain splits the Dakotas along the Missouri River, takes
+	// in western Nebraska and four Kansas counties, follows the Oklahoma
+	// panhandle and New Mexico line, and leaves El Paso and Hudspeth
+	// counties in Mountain time.
+	centralMountain = []point{
+		{-104.05, 50.0}, {-104.05, 48.0}, {-103.0, 47.6}, {-102.0, 47.3},
+		{-101.4, 46.8}, {-100.6, 46.0}, {-100.6, 45.9}, {-100.4, 44.4},
+		{-101.0, 44.0}, {-101.2, 43.5}, {-101.2, 42.0}, {-101.3, 41.0},
+		{-101.4, 40.0}, {-101.4, 37.75}, {-102.05, 37.75}, {-102.05, 37.0},
+		{-103.0, 37.0}, {-103.05, 32.0}, {-104.9, 32.0}, {-104.9, 24.0},
+	}
+
+	// mountainPacific follows the Idaho–Montana line to the Salmon River,
+	// puts Malheur County, Oregon, in Mountain time, and runs down the
+	// Nevada–Utah line and the Colorado River.
+	mountainPacific = []point{
+		{-116.05, 50.0}, {-116.05, 47.9}, {-115.7, 47.4}, {-114.6, 45.6},
+		{-116.0, 45.4}, {-116.6, 45.45}, {-116.9, 44.3}, {-118.2, 44.3},
+		{-118.2, 42.0}, {-114.05, 42.0}, {-114.05, 36.2}, {-114.7, 36.0},
+		{-114.6, 35.0}, {-114.4, 34.3}, {-114.7, 33.0}, {-114.8, 32.5},
+		{-114.8, 24.0},
+	}
+)
+
+// zones are checked in order, so Arizona, which keeps standard time all
+// year, is carved out of the Mountain band before it is checked.
+var zones = []zone{
+	{"America/Phoenix", []point{
+		{-114.05, 37.0}, {-109.05, 37.0}, {-109.05, 31.33}, {-111.07, 31.33},
+		{-114.8, 32.5}, {-114.7, 33.0}, {-114.4, 34.3}, {-114.6, 35.0},
+		{-114.7, 36.0}, {-114.05, 36.2},
+	}},
+	{"America/New_York", between(eastCentral, []point{{-60.0, 50.0}, {-60.0, 24.0}})},
+	{"America/Chicago", between(centralMountain, eastCentral)},
+	{"America/Denver", between(mountainPacific, centralMountain)},
+	{"America/Los_Angeles", between([]point{{-130.0, 50.0}, {-130.0, 24.0}}, mountainPacific)},
+	{"America/Adak", []point{{-180.0, 50.0}, {-169.0, 50.0}, {-169.0, 56.0}, {-180.0, 56.0}}},
+	{"America/Anchorage", []point{{-169.0, 51.0}, {-129.99, 51.0}, {-129.99, 72.0}, {-169.0, 72.0}}},
+	{"Pacific/Honolulu", []point{{-161.0, 18.5}, {-154.5, 18.5}, {-154.5, 22.5}, {-161.0, 22.5}}},
+	{"America/Puerto_Rico", []point{{-67.5, 17.8}, {-65.2, 17.8}, {-65.2, 18.7}, {-67.5, 18.7}}},
+	{"America/St_Thomas", []point{{-65.1, 17.6}, {-64.5, 17.6}, {-64.5, 18.5}, {-65.1, 18.5}}},
+	{"Pacific/Guam", []point{{144.5, 13.2}, {145.0, 13.2}, {145.0, 13.7}, {144.5, 13.7}}},
+}
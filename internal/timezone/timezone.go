@@ -0,0 +1,112 @@
+package timezone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	// Zone names must resolve on machines without a zoneinfo database,
+	// such as Windows and minimal containers.
+	_ "time/tzdata"
+)
+
+var (
+	ErrUnknownZone = errors.New("unknown time zone")
+)
+
+// Resolver finds the IANA time zone for a coordinate.
+type Resolver interface {
+	TimeZone(ctx context.Context, lat, lng float64) (string, error)
+}
+
+// Fixed is a Resolver that answers with the same zone everywhere, for a
+// configured override.
+type Fixed string
+
+// TimeZone returns f.
+func (f Fixed) TimeZone(ctx context.Context, lat, lng float64) (string, error) {
+	return string(f), nil
+}
+
+// Embedded resolves zones from the boundary dataset built into the binary,
+// so no request leaves the machine. It covers the US and its territories
+// at roughly county-level accuracy; elsewhere it falls back to the
+// nautical zone for the longitude, e.g. Etc/GMT-9 east of 127.5°E.
+type Embedded struct{}
+
+// TimeZone returns the zone containing lat, lng.
+func (Embedded) TimeZone(ctx context.Context, lat, lng float64) (string, error) {
+	for _, z := range zones {
+		if z.contains(lng, lat) {
+			return z.name, nil
+		}
+	}
+	// Etc/GMT names are POSIX-style: Etc/GMT+8 is eight hours behind UTC.
+	offset := int(math.Round(lng / 15))
+	switch {
+	case offset == 0:
+		return "Etc/GMT", nil
+	case offset > 0:
+		return fmt.Sprintf("Etc/GMT-%d", min(offset, 12)), nil
+	default:
+		return fmt.Sprintf("Etc/GMT+%d", min(-offset, 12)), nil
+	}
+}
+
+// Load returns the location for an IANA zone name. An empty name is the
+// machine's local zone.
+func Load(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownZone, name)
+	}
+	return loc, nil
+}
+
+// Today returns midnight at the start of now's date in the named zone, or
+// in the local zone if name is empty or unknown.
+func Today(name string, now time.Time) time.Time {
+	loc, err := Load(name)
+	if err != nil {
+		loc = time.Local
+	}
+	y, m, d := now.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// point is a longitude, latitude pair.
+type point struct{ lng, lat float64 }
+
+// zone is a named region bounded by a polygon.
+type zone struct {
+	name    string
+	polygon []point
+}
+
+// contains reports whether lng, lat is inside z, by ray casting.
+func (z zone) contains(lng, lat float64) bool {
+	in := false
+	p := z.polygon
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		if (p[i].lat > lat) != (p[j].lat > lat) &&
+			lng < (p[j].lng-p[i].lng)*(lat-p[i].lat)/(p[j].lat-p[i].lat)+p[i].lng {
+			in = !in
+		}
+	}
+	return in
+}
+
+// between builds the polygon for the band between two boundaries, each
+// traced north to south.
+func between(west, east []point) []point {
+	poly := append([]point{}, west...)
+	for i := len(east) - 1; i >= 0; i-- {
+		poly = append(poly, east[i])
+	}
+	return poly
+}
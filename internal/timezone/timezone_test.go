@@ -0,0 +1,83 @@
+package timezone
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEmbedded(t *testing.T) {
+	tests := []struct {
+		place    string
+		lat, lng float64
+		want     string
+	}{
+		{"New York", 40.75, -73.99, "America/New_York"},
+		{"Indianapolis", 39.77, -86.16, "America/New_York"},
+		{"Gary", 41.60, -87.35, "America/Chicago"},
+		{"Evansville", 37.97, -87.57, "America/Chicago"},
+		{"Louisville", 38.25, -85.76, "America/New_York"},
+		{"Bowling Green", 36.99, -86.44, "America/Chicago"},
+		{"Knoxville", 35.96, -83.92, "America/New_York"},
+		{"Chattanooga", 35.05, -85.31, "America/New_York"},
+		{"Nashville", 36.16, -86.78, "America/Chicago"},
+		{"Tallahassee", 30.44, -84.28, "America/New_York"},
+		{"Panama City", 30.16, -85.66, "America/Chicago"},
+		{"Chicago", 41.88, -87.62, "America/Chicago"},
+		{"Bismarck", 46.81, -100.78, "America/Chicago"},
+		{"Dickinson", 46.88, -102.79, "America/Denver"},
+		{"Pierre", 44.37, -100.35, "America/Chicago"},
+		{"Rapid City", 44.08, -103.23, "America/Denver"},
+		{"Amarillo", 35.20, -101.83, "America/Chicago"},
+		{"El Paso", 31.76, -106.49, "America/Denver"},
+		{"Denver", 39.74, -104.99, "America/Denver"},
+		{"Phoenix", 33.45, -112.07, "America/Phoenix"},
+		{"Salt Lake City", 40.76, -111.89, "America/Denver"},
+		{"Boise", 43.62, -116.20, "America/Denver"},
+		{"Coeur d'Alene", 47.68, -116.78, "America/Los_Angeles"},
+		{"Las Vegas", 36.17, -115.14, "America/Los_Angeles"},
+		{"Menlo Park", 37.45, -122.18, "America/Los_Angeles"},
+		{"Seattle", 47.61, -122.33, "America/Los_Angeles"},
+		{"Anchorage", 61.22, -149.90, "America/Anchorage"},
+		{"Honolulu", 21.31, -157.86, "Pacific/Honolulu"},
+		{"San Juan", 18.47, -66.11, "America/Puerto_Rico"},
+		{"Tokyo", 35.68, 139.69, "Etc/GMT-9"},
+		{"London", 51.51, -0.13, "Etc/GMT"},
+	}
+	for _, tt := range tests {
+		got, err := Embedded{}.TimeZone(context.Background(), tt.lat, tt.lng)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.place, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.place, got, tt.want)
+		}
+		if _, err := Load(got); err != nil {
+			t.Errorf("%s: %v", tt.place, err)
+		}
+	}
+}
+
+func TestToday(t *testing.T) {
+	// 06:30 UTC is still the previous evening on the US west coast.
+	now := time.Date(2025, 6, 18, 6, 30, 0, 0, time.UTC)
+	if got := Today("America/Los_Angeles", now); got.Format("2006-01-02") != "2025-06-17" {
+		t.Errorf("Los Angeles: got %s", got)
+	}
+	if got := Today("America/New_York", now); got.Format("2006-01-02") != "2025-06-18" {
+		t.Errorf("New York: got %s", got)
+	}
+	if got := Today("Nowhere/Special", now); got.Location() != time.Local {
+		t.Errorf("unknown zones should fall back to local time, got %s", got.Location())
+	}
+}
+
+func TestLoad(t *testing.T) {
+	if _, err := Load("Mars/Olympus_Mons"); !errors.Is(err, ErrUnknownZone) {
+		t.Errorf("expected ErrUnknownZone, got %v", err)
+	}
+	if loc, err := Load(""); err != nil || loc != time.Local {
+		t.Errorf("empty name should be local time, got %v, %v", loc, err)
+	}
+}
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var (
+	ErrUnknownExporter = errors.New("unknown trace exporter")
+)
+
+// Exporters.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"   // OTLP over HTTP to a collector
+	ExporterStdout = "stdout" // pretty-printed JSON, for local testing
+)
+
+// ValidExporter reports whether name is an exporter Setup accepts. Empty
+// means none.
+func ValidExporter(name string) bool {
+	switch name {
+	case "", ExporterNone, ExporterOTLP, ExporterStdout:
+		return true
+	}
+	return false
+}
+
+// Options configure Setup.
+type Options struct {
+	Exporter string       // ExporterNone (default), ExporterOTLP, or ExporterStdout
+	Endpoint string       // OTLP collector URL; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318
+	Version  string       // reported as service.version
+	Stdout   io.Writer    // where ExporterStdout writes; nil means os.Stderr, keeping spans out of piped output
+	Log      *slog.Logger // receives export errors; nil keeps OpenTelemetry's default
+}
+
+// Setup installs a global tracer provider and W3C trace-context propagator
+// for opts. With no exporter it does nothing, leaving OpenTelemetry's no-op
+// provider in place, so instrumented code costs next to nothing. The
+// returned shutdown flushes pending spans and must be called before exit.
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	var exp sdktrace.SpanExporter
+	switch opts.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var o []otlptracehttp.Option
+		if opts.Endpoint != "" {
+			o = append(o, otlptracehttp.WithEndpointURL(opts.Endpoint))
+		}
+		exp, err = otlptracehttp.New(ctx, o...)
+	case ExporterStdout:
+		w := opts.Stdout
+		if w == nil {
+			w = os.Stderr
+		}
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(w), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("%w %q (use %s, %s, or %s)", ErrUnknownExporter, opts.Exporter, ExporterNone, ExporterOTLP, ExporterStdout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating %s trace exporter: %w", opts.Exporter, err)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", "pollenow"),
+		attribute.String("service.version", opts.Version),
+	)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(res))
+	if opts.Log != nil {
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			opts.Log.Warn("exporting traces failed", "err", err)
+		}))
+	}
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return tp.Shutdown, nil
+}
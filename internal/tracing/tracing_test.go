@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+	_, span := otel.Tracer("test").Start(context.Background(), "noop")
+	if span.SpanContext().IsValid() {
+		t.Error("disabled tracing should record nothing")
+	}
+}
+
+func TestSetupStdout(t *testing.T) {
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	var buf bytes.Buffer
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterStdout, Version: "1.2.3", Stdout: &buf})
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	_, span := otel.Tracer("test").Start(context.Background(), "forecast.GetForecast")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `"Name": "forecast.GetForecast"`) || !strings.Contains(out, "1.2.3") {
+		t.Errorf("stdout export missing the span or version:\n%s", out)
+	}
+}
+
+func TestSetupUnknown(t *testing.T) {
+	if _, err := Setup(context.Background(), Options{Exporter: "zipkin"}); !errors.Is(err, ErrUnknownExporter) {
+		t.Errorf("expected ErrUnknownExporter, got %v", err)
+	}
+	if ValidExporter("zipkin") || !ValidExporter("") {
+		t.Error("ValidExporter")
+	}
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shunito/pollenow/internal/doctor"
+)
+
+// doctorMark returns the colored mark for a check status.
+func (r *Renderer) doctorMark(s doctor.Status) string {
+	switch s {
+	case doctor.Pass:
+		return r.st.good.Render(r.glyphs.pass)
+	case doctor.Warn:
+		return r.st.caution.Render(r.glyphs.warn)
+	case doctor.Fail:
+		return r.st.failure.Render(r.glyphs.fail)
+	default:
+		return r.st.recommendation.Render("-")
+	}
+}
+
+// RenderDoctor prints diagnostic results to stdout. See Renderer.Doctor.
+func RenderDoctor(checks []doctor.Check) {
+	_ = stdout().Doctor(checks)
+}
+
+// Doctor writes diagnostic results with a pass/fail mark per check.
+func (r *Renderer) Doctor(checks []doctor.Check) error {
+	var b strings.Builder
+	fmt.Fprintln(&b, r.st.title.Render(r.msg.T("doctor.title")))
+	fmt.Fprintln(&b)
+
+	counts := map[doctor.Status]int{}
+	for _, c := range checks {
+		counts[c.Status]++
+		fmt.Fprintf(&b, "  %s %-16s %s\n", r.doctorMark(c.Status), c.Name, c.Detail)
+		if c.Hint != "" && c.Status != doctor.Pass {
+			fmt.Fprintln(&b, r.st.recommendation.Render("      "+r.glyphs.arrow+" "+c.Hint))
+		}
+	}
+
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, r.msg.T("doctor.totals", counts[doctor.Pass], counts[doctor.Warn], counts[doctor.Fail]))
+	fmt.Fprintln(&b)
+	return r.write(b.String())
+}
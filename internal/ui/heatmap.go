@@ -0,0 +1,24 @@
+package ui
+
+import "github.com/shunito/pollenow/internal/heatmap"
+
+// RenderMap prints a heatmap to stdout. See Renderer.Map.
+func RenderMap(location, plant string, zoom int, m *heatmap.Map, protocol string, cols int) error {
+	return stdout().Map(location, plant, zoom, m, protocol, cols)
+}
+
+// Map writes a heatmap titled for the pollen type and location, drawn
+// with protocol at cols columns.
+func (r *Renderer) Map(location, plant string, zoom int, m *heatmap.Map, protocol string, cols int) error {
+	header := r.st.title.Render(r.msg.T("map.title", r.msg.T("type."+plant))) + "\n" +
+		r.st.location.Render(r.msg.T("map.location", location, zoom)) + "\n\n"
+	if err := r.write(header); err != nil {
+		return err
+	}
+
+	if err := heatmap.Render(r.w, m, protocol, cols); err != nil {
+		return err
+	}
+
+	return r.write("\n" + r.st.legend.Render(r.msg.T("map.legend")) + "\n")
+}
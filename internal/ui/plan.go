@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shunito/pollenow/internal/forecast"
+	"github.com/shunito/pollenow/internal/plan"
+)
+
+// RenderPlan prints medication advice to stdout. See Renderer.Plan.
+func RenderPlan(result *forecast.Result, advice []plan.Advice, now time.Time) {
+	_ = stdout().Plan(result, advice, now)
+}
+
+// Plan writes medication advice for the forecast.
+func (r *Renderer) Plan(result *forecast.Result, advice []plan.Advice, now time.Time) error {
+	var b strings.Builder
+	fmt.Fprintln(&b, r.st.title.Render(r.msg.T("plan.title")))
+	fmt.Fprintln(&b, r.st.location.Render(result.Location.DisplayName))
+	fmt.Fprintln(&b)
+
+	if len(advice) == 0 {
+		fmt.Fprintln(&b, r.st.good.Render(r.glyphs.pass+" "+r.msg.T("plan.none")))
+		fmt.Fprintln(&b)
+		return r.write(b.String())
+	}
+
+	for _, a := range advice {
+		if a.Taken {
+			fmt.Fprintln(&b, r.st.good.Render(r.glyphs.pass+" "+r.msg.T("plan.taken", a.Medication, a.Date)))
+			continue
+		}
+		fmt.Fprintln(&b, r.st.warning.Render(r.glyphs.pill+a.Message(r.msg, now)))
+		fmt.Fprintln(&b, r.st.recommendation.Render(r.msg.T("plan.take_by", r.msg.Weekday(a.TakeAt.Weekday()), a.TakeAt.Format("15:04"), a.Medication)))
+	}
+	fmt.Fprintln(&b)
+	return r.write(b.String())
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+	"github.com/shunito/pollenow/internal/baseline"
+	"github.com/shunito/pollenow/internal/forecast"
+	"github.com/shunito/pollenow/internal/i18n"
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/theme"
+)
+
+// minWrapWidth is the narrowest column text is wrapped to; below it,
+// lines are left long rather than broken every few characters.
+const minWrapWidth = 16
+
+// Options control how a Renderer writes. The zero value writes true color
+// in the default theme, in English, with no width limit.
+type Options struct {
+	// Width is the number of columns available. A forecast table wider
+	// than this is stacked one day per block, and long lines are
+	// wrapped. Zero means no limit.
+	Width int
+	// Profile is the color profile to write with; termenv.Ascii writes
+	// plain text. The zero value is true color.
+	Profile termenv.Profile
+
+	Appearance
+
+	// Thresholds decide which levels the summary and compact output treat
+	// as high or low. The zero value is pollen.DefaultThresholds.
+	Thresholds pollen.Thresholds
+	// Messages translates PolleNow's own text. Nil is English.
+	Messages *i18n.Printer
+}
+
+// Settings are the CLI's choices for the Render functions, which write to
+// the terminal: everything in Options but the width and color profile,
+// which come from the terminal and the color mode.
+type Settings struct {
+	Appearance
+	// ColorMode is theme.ColorAuto (the default), ColorAlways, or
+	// ColorNever.
+	ColorMode  string
+	Thresholds pollen.Thresholds
+	Messages   *i18n.Printer
+}
+
+// settings are read only by TerminalOptions, which the Render functions
+// build their Renderers from.
+var settings Settings
+
+// Configure sets what the Render functions use from now on.
+func Configure(s Settings) {
+	settings = s
+}
+
+// TerminalOptions returns the configured settings for writing to f, with
+// its width when it is a terminal and the color profile the color mode
+// gives it.
+func TerminalOptions(f *os.File) Options {
+	opts := Options{
+		Profile:    profileFor(f, settings.ColorMode),
+		Appearance: settings.Appearance,
+		Thresholds: settings.Thresholds,
+		Messages:   settings.Messages,
+	}
+	if w, _, err := term.GetSize(f.Fd()); err == nil && w > 0 {
+		opts.Width = w
+	}
+	return opts
+}
+
+// Renderer writes forecasts and errors to an io.Writer, so they can be
+// embedded in servers, TUIs, and tests as well as printed.
+type Renderer struct {
+	w      io.Writer
+	width  int
+	st     styles
+	glyphs glyphSet
+	th     pollen.Thresholds
+	msg    *i18n.Printer
+}
+
+// NewRenderer returns a Renderer writing to w as opts describe.
+func NewRenderer(w io.Writer, opts Options) *Renderer {
+	if opts.Theme.Name == "" {
+		opts.Theme, _ = theme.Get(theme.Default)
+	}
+	if opts.Thresholds == (pollen.Thresholds{}) {
+		opts.Thresholds = pollen.DefaultThresholds
+	}
+	if opts.Messages == nil {
+		opts.Messages = i18n.English
+	}
+	lr := lipgloss.NewRenderer(w)
+	lr.SetColorProfile(opts.Profile)
+	r := &Renderer{
+		w:      w,
+		width:  opts.Width,
+		st:     newStyles(lr, opts.Appearance),
+		glyphs: unicodeGlyphs,
+		th:     opts.Thresholds,
+		msg:    opts.Messages,
+	}
+	if opts.ASCII {
+		r.glyphs = asciiGlyphs
+	}
+	return r
+}
+
+// stdout returns a Renderer for the terminal's standard output.
+func stdout() *Renderer {
+	return NewRenderer(os.Stdout, TerminalOptions(os.Stdout))
+}
+
+// RenderForecast prints the full forecast to stdout. See Renderer.Forecast.
+func RenderForecast(result *forecast.Result, norms *baseline.Baseline) {
+	_ = stdout().Forecast(result, norms)
+}
+
+// RenderCompact prints a one-line summary to stdout.
+func RenderCompact(result *forecast.Result) {
+	_ = stdout().Compact(result)
+}
+
+// RenderError prints a styled error message to stderr.
+func RenderError(err error) {
+	_ = NewRenderer(os.Stderr, TerminalOptions(os.Stderr)).Error(err)
+}
+
+// Colorize renders text in the color for level as stdout would show it,
+// honoring the theme, API colors, and color mode. It backs the color
+// function in --format.
+func Colorize(level pollen.PollenLevel, text string) string {
+	return stdout().Colorize(level, text)
+}
+
+// Colorize renders text in the color for level.
+func (r *Renderer) Colorize(level pollen.PollenLevel, text string) string {
+	return r.st.level(level).Render(text)
+}
+
+// Error writes a styled error message.
+func (r *Renderer) Error(err error) error {
+	return r.write(r.st.err.Render(r.msg.T("error.prefix", err.Error())) + "\n")
+}
+
+// write sends s to the writer in one call.
+func (r *Renderer) write(s string) error {
+	_, err := io.WriteString(r.w, s)
+	return err
+}
+
+// paragraph wraps text to the width after prefix, indents continuation
+// lines to line up with the text, and styles each line. Styling lines one
+// at a time keeps lipgloss from padding them to a common width.
+func (r *Renderer) paragraph(style lipgloss.Style, prefix, text string) string {
+	indent := lipgloss.Width(prefix)
+	lines := []string{text}
+	if r.width-indent >= minWrapWidth {
+		lines = strings.Split(ansi.Wrap(text, r.width-indent, ""), "\n")
+	}
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = style.Render(prefix + line)
+		} else {
+			lines[i] = style.Render(strings.Repeat(" ", indent) + line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
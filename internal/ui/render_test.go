@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/shunito/pollenow/internal/forecast"
+	"github.com/shunito/pollenow/internal/geocoding"
+	"github.com/shunito/pollenow/internal/i18n"
+	"github.com/shunito/pollenow/internal/pollen"
+)
+
+func level(v int, cat string, inSeason bool) pollen.PollenLevel {
+	return pollen.PollenLevel{Level: &v, Category: cat, InSeason: inSeason}
+}
+
+func testResult() *forecast.Result {
+	today := time.Now().Format(pollen.DateLayout)
+	return &forecast.Result{
+		Location: geocoding.Location{DisplayName: "Menlo Park, CA 94025, USA"},
+		Forecast: &pollen.Forecast{Days: []pollen.DayForecast{{
+			Date:                  today,
+			Grass:                 level(2, "Low", true),
+			Tree:                  level(4, "High", true),
+			Weed:                  level(0, "None", false),
+			HealthRecommendations: []string{"Limit outdoor activity during peak pollen hours, especially on dry and windy days."},
+		}}},
+	}
+}
+
+func TestForecastTable(t *testing.T) {
+	var b strings.Builder
+	if err := NewRenderer(&b, Options{Profile: termenv.Ascii}).Forecast(testResult(), nil); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{"Menlo Park", "Tree pollen is HIGH today", "│Today", "4 High *", "  • Limit outdoor activity"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("plain profile wrote escape codes:\n%s", out)
+	}
+}
+
+func TestForecastNarrow(t *testing.T) {
+	const width = 30
+	var b strings.Builder
+	if err := NewRenderer(&b, Options{Width: width, Profile: termenv.Ascii}).Forecast(testResult(), nil); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if strings.Contains(out, "┌") {
+		t.Errorf("expected the stacked layout at width %d:\n%s", width, out)
+	}
+	if !strings.Contains(out, "Today\n  🌱 Grass  ■ 2 Low *") {
+		t.Errorf("stacked layout:\n%s", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if w := lipgloss.Width(line); w > width {
+			t.Errorf("line is %d columns wide, want at most %d: %q", w, width, line)
+		}
+	}
+}
+
+func TestCompactAndError(t *testing.T) {
+	var b strings.Builder
+	r := NewRenderer(&b, Options{Profile: termenv.TrueColor})
+	if err := r.Compact(testResult()); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Menlo Park, CA 94025, USA: Grass Low | Tree HIGH | Weed None\n"; b.String() != want {
+		t.Errorf("compact: got %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	if err := r.Error(errors.New("boom")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "\x1b[") || !strings.Contains(b.String(), "boom") {
+		t.Errorf("error should be colored with a true-color profile: %q", b.String())
+	}
+}
+
+func TestRendererOptions(t *testing.T) {
+	var b strings.Builder
+	r := NewRenderer(&b, Options{
+		Profile:    termenv.Ascii,
+		Appearance: Appearance{ASCII: true},
+		Thresholds: pollen.Thresholds{High: 5, Low: 2},
+		Messages:   i18n.New("ja"),
+	})
+	if err := r.Forecast(testResult(), nil); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if strings.Contains(out, "HIGH") {
+		t.Errorf("a tree level of 4 is not high with a threshold of 5:\n%s", out)
+	}
+	if !strings.Contains(out, "今日") || !strings.Contains(out, "+---") || strings.Contains(out, "🌱") {
+		t.Errorf("expected Japanese text in ASCII:\n%s", out)
+	}
+
+	// Another renderer is unaffected.
+	b.Reset()
+	if err := NewRenderer(&b, Options{Profile: termenv.Ascii}).Compact(testResult()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Tree HIGH") {
+		t.Errorf("default options: %q", b.String())
+	}
+}
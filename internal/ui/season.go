@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shunito/pollenow/internal/season"
+)
+
+const weeksPerYear = 53
+
+// RenderSeasons prints the season chart to stdout. See Renderer.Seasons.
+func RenderSeasons(location string, seasons []season.Season, years []int, now time.Time) {
+	_ = stdout().Seasons(location, seasons, years, now)
+}
+
+// Seasons writes a calendar chart of seasons, one row per pollen type
+// (or plant) and year, newest year first, followed by a list of dates.
+func (r *Renderer) Seasons(location string, seasons []season.Season, years []int, now time.Time) error {
+	var b strings.Builder
+	fmt.Fprintln(&b, r.st.title.Render(r.msg.T("season.title")))
+	fmt.Fprintln(&b, r.st.location.Render(location))
+	fmt.Fprintln(&b)
+
+	if len(seasons) == 0 {
+		fmt.Fprintln(&b, r.st.recommendation.Render(r.msg.T("season.none")))
+		return r.write(b.String())
+	}
+
+	type row struct{ code, name string }
+	var rows []row
+	seen := make(map[string]bool)
+	for _, s := range seasons {
+		if !seen[s.Code] {
+			seen[s.Code] = true
+			rows = append(rows, row{s.Code, s.Name})
+		}
+	}
+
+	const labelWidth = 16
+	fmt.Fprintln(&b, strings.Repeat(" ", labelWidth)+r.st.legend.Render(r.monthHeader()))
+
+	for _, rw := range rows {
+		for _, year := range years {
+			var cells strings.Builder
+			for week := 0; week < weeksPerYear; week++ {
+				start := time.Date(year, 1, 1+7*week, 0, 0, 0, 0, time.UTC)
+				end := start.AddDate(0, 0, 6)
+				switch {
+				case covered(seasons, rw.code, start, end):
+					cells.WriteString(r.st.seasonOn.Render(r.glyphs.inSeason))
+				case year == now.Year() && inRange(now, start, end):
+					cells.WriteString(r.st.today.Render(r.glyphs.thisWeek))
+				default:
+					cells.WriteString(r.st.seasonOff.Render(r.glyphs.offSeason))
+				}
+			}
+			label := fmt.Sprintf("%-10s %d ", truncate(rw.name, 10), year)
+			fmt.Fprintln(&b, label+cells.String())
+		}
+	}
+	fmt.Fprintln(&b, r.st.legend.Render(strings.Repeat(" ", labelWidth)+r.msg.T("season.legend", r.glyphs.inSeason, r.glyphs.thisWeek)))
+	fmt.Fprintln(&b)
+
+	for _, s := range seasons {
+		if !containsYear(years, s.Year) {
+			continue
+		}
+		end := r.msg.ShortDate(s.End)
+		if s.Ongoing {
+			end = r.msg.T("season.ongoing")
+		}
+		line := fmt.Sprintf("  %-10s %d: %s", truncate(s.Name, 10), s.Year, r.msg.T("season.range", r.msg.ShortDate(s.Start), end, s.Days()))
+		if s.PeakLevel >= 0 {
+			line += r.msg.T("season.peak", s.PeakLevel, r.msg.ShortDate(s.PeakDate))
+		}
+		fmt.Fprintln(&b, r.st.recommendation.Render(line))
+	}
+	fmt.Fprintln(&b)
+	return r.write(b.String())
+}
+
+// monthHeader labels the week columns with month initials.
+func (r *Renderer) monthHeader() string {
+	header := []rune(strings.Repeat(" ", weeksPerYear))
+	initials := []rune(r.msg.T("season.months"))
+	for m := time.January; m <= time.December && int(m) <= len(initials); m++ {
+		week := (time.Date(2001, m, 1, 0, 0, 0, 0, time.UTC).YearDay() - 1) / 7
+		header[week] = initials[m-1]
+	}
+	return string(header)
+}
+
+func covered(seasons []season.Season, code string, start, end time.Time) bool {
+	for _, s := range seasons {
+		if s.Code == code && !s.End.Before(start) && !s.Start.After(end) {
+			return true
+		}
+	}
+	return false
+}
+
+func inRange(t, start, end time.Time) bool {
+	d := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return !d.Before(start) && !d.After(end)
+}
+
+func containsYear(years []int, y int) bool {
+	for _, v := range years {
+		if v == y {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -2,201 +2,229 @@ package ui
 
 import (
 	"fmt"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"github.com/shunito/pollenow/internal/baseline"
 	"github.com/shunito/pollenow/internal/forecast"
 	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/timezone"
 )
 
-// Color constants for pollen categories.
-var categoryColors = map[string]lipgloss.Color{
-	"None":      lipgloss.Color("#6b7280"),
-	"Very Low":  lipgloss.Color("#10b981"),
-	"Low":       lipgloss.Color("#10b981"),
-	"Moderate":  lipgloss.Color("#f59e0b"),
-	"High":      lipgloss.Color("#ef4444"),
-	"Very High": lipgloss.Color("#dc2626"),
-	"No Data":   lipgloss.Color("#6b7280"),
-}
-
-var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("#4361ee")).
-			MarginBottom(1)
-
-	locationStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9ca3af"))
-
-	cachedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6b7280")).
-			Italic(true)
-
-	warningStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f59e0b")).
-			Bold(true)
-
-	recommendationStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#9ca3af"))
-
-	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ef4444")).
-			Bold(true)
+// Forecast writes the full forecast: a summary for today, a table of
+// days, and health recommendations. When norms is non-nil and has history
+// for the forecast dates, a column comparing each day with the seasonal
+// norm is added. A table too wide for the width is stacked instead.
+func (r *Renderer) Forecast(result *forecast.Result, norms *baseline.Baseline) error {
+	var b strings.Builder
+	st := r.st
 
-	legendStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6b7280")).
-			Italic(true)
-)
-
-// RenderForecast prints the full forecast table to stdout.
-func RenderForecast(result *forecast.Result) {
 	// Title
-	fmt.Println(titleStyle.Render("PolleNow - Pollen Forecast"))
+	b.WriteString(st.title.Render(r.msg.T("forecast.title")) + "\n")
 
 	// Location
-	locLine := locationStyle.Render(result.Location.DisplayName)
+	locLine := st.location.Render(result.Location.DisplayName)
 	if result.Cached {
 		minutes := int(result.CacheAge.Minutes())
-		locLine += " " + cachedStyle.Render(fmt.Sprintf("(cached %dm ago)", minutes))
+		locLine += " " + st.cached.Render(r.msg.T("forecast.cached", minutes))
 	}
-	fmt.Println(locLine)
-	fmt.Println()
+	b.WriteString(locLine + "\n\n")
 
 	if len(result.Forecast.Days) == 0 {
-		fmt.Println(recommendationStyle.Render("No forecast data available."))
-		return
+		b.WriteString(st.recommendation.Render(r.msg.T("forecast.no_data")) + "\n")
+		return r.write(b.String())
 	}
 
 	// Summary line for today
+	comparisons := make([]map[string]baseline.Comparison, len(result.Forecast.Days))
+	hasNorms := false
+	for i, day := range result.Forecast.Days {
+		comparisons[i] = norms.CompareDay(day)
+		for _, c := range comparisons[i] {
+			if c != baseline.Unknown {
+				hasNorms = true
+			}
+		}
+	}
+
 	today := result.Forecast.Days[0]
-	summary := buildSummary(today)
-	if summary != "" {
-		fmt.Println(summary)
-		fmt.Println()
+	if summary := r.summary(today, comparisons[0]); summary != "" {
+		b.WriteString(summary + "\n\n")
 	}
 
-	// Forecast table
+	// Forecast table, with days named as seen from the location
+	now := timezone.Today(result.Location.TimeZone, time.Now())
 	hasInSeason := false
 	rows := make([][]string, 0, len(result.Forecast.Days))
-	for _, day := range result.Forecast.Days {
-		grassCell, grassSeason := formatCell(day.Grass)
-		treeCell, treeSeason := formatCell(day.Tree)
-		weedCell, weedSeason := formatCell(day.Weed)
+	for i, day := range result.Forecast.Days {
+		grassCell, grassSeason := r.cell(day.Grass)
+		treeCell, treeSeason := r.cell(day.Tree)
+		weedCell, weedSeason := r.cell(day.Weed)
 		if grassSeason || treeSeason || weedSeason {
 			hasInSeason = true
 		}
-		rows = append(rows, []string{day.DayName, grassCell, treeCell, weedCell})
+		row := []string{r.dayName(day, now), grassCell, treeCell, weedCell}
+		if hasNorms {
+			row = append(row, r.formatNormCell(day, comparisons[i]))
+		}
+		rows = append(rows, row)
+	}
+
+	headers := []string{
+		r.msg.T("table.day"),
+		r.glyphs.grass + r.msg.T("type.grass"),
+		r.glyphs.tree + r.msg.T("type.tree"),
+		r.glyphs.weed + r.msg.T("type.weed"),
+	}
+	if hasNorms {
+		headers = append(headers, r.msg.T("table.vs_typical"))
 	}
 
 	t := table.New().
-		Border(lipgloss.NormalBorder()).
-		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("#4b5563"))).
-		Headers("Day", "🌱 Grass", "🌳 Tree", "🌿 Weed").
+		Border(r.glyphs.border).
+		BorderStyle(st.border).
+		Headers(headers...).
 		Rows(rows...).
 		StyleFunc(func(row, col int) lipgloss.Style {
 			if row == table.HeaderRow {
-				return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#e5e7eb"))
+				return st.header
 			}
-			return lipgloss.NewStyle()
-		})
-
-	fmt.Println(t)
+			return st.r.NewStyle()
+		}).
+		String()
+	if r.width > 0 && lipgloss.Width(t) > r.width {
+		t = r.stacked(headers, rows)
+	}
+	b.WriteString(t + "\n")
 
 	if hasInSeason {
-		fmt.Println(legendStyle.Render("* = in season"))
+		b.WriteString(r.paragraph(st.legend, "", r.msg.T("legend.in_season")) + "\n")
+	}
+	if hasNorms {
+		b.WriteString(r.paragraph(st.legend, "", r.msg.T("legend.norms")) + "\n")
 	}
 
 	// Health recommendations (from today)
 	if len(today.HealthRecommendations) > 0 {
-		fmt.Println()
-		fmt.Println(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#e5e7eb")).Render("Health Recommendations"))
+		b.WriteString("\n" + st.header.Render(r.msg.T("forecast.health")) + "\n")
 		for _, rec := range today.HealthRecommendations {
-			fmt.Println(recommendationStyle.Render("  • " + rec))
+			b.WriteString(r.paragraph(st.recommendation, "  "+r.glyphs.bullet+" ", rec) + "\n")
 		}
 	}
 
-	fmt.Println()
+	b.WriteString("\n")
+	return r.write(b.String())
 }
 
-// RenderCompact prints a one-line summary.
-func RenderCompact(result *forecast.Result) {
+// stacked lays the table out one block per day, label beside value, for
+// widths the table would overflow.
+func (r *Renderer) stacked(headers []string, rows [][]string) string {
+	labelWidth := 0
+	for _, h := range headers[1:] {
+		labelWidth = max(labelWidth, lipgloss.Width(h))
+	}
+
+	blocks := make([]string, 0, len(rows))
+	for _, row := range rows {
+		lines := []string{r.st.header.Render(row[0])}
+		for i, cell := range row[1:] {
+			label := headers[i+1]
+			pad := strings.Repeat(" ", labelWidth-lipgloss.Width(label))
+			lines = append(lines, "  "+label+pad+"  "+cell)
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// Compact writes a one-line summary. It is never wrapped, so scripts can
+// rely on one line per forecast.
+func (r *Renderer) Compact(result *forecast.Result) error {
 	if len(result.Forecast.Days) == 0 {
-		fmt.Println("No forecast data available.")
-		return
+		return r.write(r.msg.T("forecast.no_data") + "\n")
 	}
 
 	today := result.Forecast.Days[0]
-	parts := []string{
-		fmt.Sprintf("Grass %s", compactLevel(today.Grass)),
-		fmt.Sprintf("Tree %s", compactLevel(today.Tree)),
-		fmt.Sprintf("Weed %s", compactLevel(today.Weed)),
+	parts := make([]string, 0, 3)
+	for _, tl := range today.Types() {
+		parts = append(parts, r.typeName(tl)+" "+r.compactLevel(tl.Level))
 	}
 
 	loc := result.Location.DisplayName
-	fmt.Printf("%s: %s\n", loc, strings.Join(parts, " | "))
+	return r.write(fmt.Sprintf("%s: %s\n", loc, strings.Join(parts, " | ")))
 }
 
-// RenderError prints a styled error message to stderr.
-func RenderError(err error) {
-	fmt.Fprintln(os.Stderr, errorStyle.Render("Error: "+err.Error()))
-}
+// summary creates an actionable summary line for today's forecast,
+// noting how the levels compare with the seasonal norm when known.
+func (r *Renderer) summary(day pollen.DayForecast, cmp map[string]baseline.Comparison) string {
+	th := r.th
+	st := r.st
 
-// buildSummary creates an actionable summary line for today's forecast.
-func buildSummary(day pollen.DayForecast) string {
-	highest := ""
-	highestCategory := ""
-
-	for _, entry := range []struct {
-		name  string
-		level pollen.PollenLevel
-	}{
-		{"Grass", day.Grass},
-		{"Tree", day.Tree},
-		{"Weed", day.Weed},
-	} {
-		if entry.level.Level != nil && *entry.level.Level >= 4 {
-			if highest == "" || *entry.level.Level > 4 {
-				highest = entry.name
-				highestCategory = entry.level.Category
-			}
+	if alert, ok := th.Alert(day); ok {
+		note := ""
+		if c := cmp[alert.Code]; c != baseline.Unknown {
+			note = r.msg.T("summary.norm", r.comparison(c))
 		}
+		return r.paragraph(st.warning, r.glyphs.warning,
+			r.msg.T("summary.alert", r.typeName(alert), strings.ToUpper(alert.Level.Category), note),
+		)
 	}
 
-	if highest != "" {
-		return warningStyle.Render(
-			fmt.Sprintf("⚠ %s pollen is %s today — consider limiting outdoor activity", highest, strings.ToUpper(highestCategory)),
-		)
+	var unusual []string
+	for _, tl := range day.Types() {
+		if c := cmp[tl.Code]; c == baseline.Above || c == baseline.Below {
+			unusual = append(unusual, r.msg.T("summary.unusual", r.typeName(tl), tl.Level.Category, r.comparison(c)))
+		}
 	}
 
-	// Check if everything is low
-	allLow := true
-	for _, level := range []pollen.PollenLevel{day.Grass, day.Tree, day.Weed} {
-		if level.Level != nil && *level.Level > 2 {
-			allLow = false
-			break
+	if th.AllLow(day) {
+		line := r.paragraph(st.good, "", r.msg.T("summary.all_low"))
+		if len(unusual) > 0 {
+			line += "\n" + r.paragraph(st.recommendation, "  ", strings.Join(unusual, r.glyphs.sep))
 		}
+		return line
 	}
-	if allLow {
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#10b981")).Render("✓ All pollen levels are low today")
+
+	if len(unusual) > 0 {
+		return r.paragraph(st.recommendation, "", strings.Join(unusual, r.glyphs.sep))
 	}
 
 	return ""
 }
 
-// formatCell formats a pollen level for the table.
-func formatCell(level pollen.PollenLevel) (string, bool) {
-	color, ok := categoryColors[level.Category]
-	if !ok {
-		color = categoryColors["No Data"]
+// formatNormCell summarizes a day's comparison with the seasonal norm,
+// listing only the types that are above or below typical.
+func (r *Renderer) formatNormCell(day pollen.DayForecast, cmp map[string]baseline.Comparison) string {
+	var parts []string
+	known := false
+	for _, tl := range day.Types() {
+		switch c := cmp[tl.Code]; c {
+		case baseline.Above, baseline.Below:
+			parts = append(parts, r.typeName(tl)+" "+r.normSymbol(c))
+			known = true
+		case baseline.Typical:
+			known = true
+		}
 	}
 
-	style := lipgloss.NewStyle().Foreground(color)
+	switch {
+	case len(parts) > 0:
+		return strings.Join(parts, " ")
+	case known:
+		return r.normSymbol(baseline.Typical) + " " + r.msg.T("norm.typical")
+	default:
+		return "-"
+	}
+}
 
+// cell formats a pollen level for the table, reporting whether the type
+// is in season.
+func (r *Renderer) cell(level pollen.PollenLevel) (string, bool) {
+	style := r.st.level(level)
 	if level.Level == nil {
-		return style.Render("- No Data"), false
+		return style.Render("- " + r.msg.T("level.no_data")), false
 	}
 
 	cat := abbreviateCategory(level.Category)
@@ -205,7 +233,7 @@ func formatCell(level pollen.PollenLevel) (string, bool) {
 		marker = " *"
 	}
 
-	return style.Render(fmt.Sprintf("■ %d %s", *level.Level, cat)) + marker, level.InSeason
+	return style.Render(fmt.Sprintf("%s%d %s", r.glyphs.block, *level.Level, cat)) + marker, level.InSeason
 }
 
 // abbreviateCategory shortens category names for table display.
@@ -223,13 +251,55 @@ func abbreviateCategory(cat string) string {
 }
 
 // compactLevel formats a pollen level for compact output.
-func compactLevel(level pollen.PollenLevel) string {
+func (r *Renderer) compactLevel(level pollen.PollenLevel) string {
 	if level.Level == nil {
-		return "N/A"
+		return r.msg.T("level.na")
 	}
 	cat := level.Category
-	if level.Level != nil && *level.Level >= 4 {
+	if r.th.IsHigh(level) {
 		cat = strings.ToUpper(cat)
 	}
 	return cat
 }
+
+// typeName returns the translated name of a pollen type.
+func (r *Renderer) typeName(tl pollen.TypeLevel) string {
+	return r.msg.T("type." + strings.ToLower(tl.Code))
+}
+
+// comparison translates a comparison with the seasonal norm.
+func (r *Renderer) comparison(c baseline.Comparison) string {
+	switch c {
+	case baseline.Above:
+		return r.msg.T("norm.above")
+	case baseline.Below:
+		return r.msg.T("norm.below")
+	default:
+		return r.msg.T("norm.typical")
+	}
+}
+
+// dayName labels a forecast day relative to today in the current
+// language, falling back to the stored English name if its date doesn't
+// parse.
+func (r *Renderer) dayName(day pollen.DayForecast, today time.Time) string {
+	date, err := time.Parse(pollen.DateLayout, day.Date)
+	if err != nil {
+		return day.DayName
+	}
+	return r.msg.DayName(date, today)
+}
+
+// normSymbol marks a comparison with the seasonal norm.
+func (r *Renderer) normSymbol(c baseline.Comparison) string {
+	switch c {
+	case baseline.Above:
+		return r.glyphs.above
+	case baseline.Typical:
+		return r.glyphs.typical
+	case baseline.Below:
+		return r.glyphs.below
+	default:
+		return ""
+	}
+}
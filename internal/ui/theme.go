@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/shunito/pollenow/internal/pollen"
+	"github.com/shunito/pollenow/internal/theme"
+)
+
+// Appearance selects how output looks.
+type Appearance struct {
+	// Theme supplies the colors. The zero value is the default theme.
+	Theme theme.Theme
+	// ASCII replaces emoji, "■", and box-drawing characters.
+	ASCII bool
+	// APIColors colors levels with the colors the Pollen API sends,
+	// falling back to the theme where it sends none.
+	APIColors bool
+}
+
+// glyphSet holds the symbols drawn around text.
+type glyphSet struct {
+	block   string // before a level in table cells
+	bullet  string // before list items
+	sep     string // between items on one line
+	arrow   string // before hints
+	warning string // before alerts
+	pill    string // before medication reminders
+
+	grass, tree, weed string // before table headers
+
+	above, typical, below string // comparisons with the seasonal norm
+
+	pass, warn, fail string // doctor checks
+
+	inSeason, thisWeek, offSeason string // season chart cells
+
+	border lipgloss.Border
+}
+
+var unicodeGlyphs = glyphSet{
+	block:     "■ ",
+	bullet:    "•",
+	sep:       " · ",
+	arrow:     "→",
+	warning:   "⚠ ",
+	pill:      "💊 ",
+	grass:     "🌱 ",
+	tree:      "🌳 ",
+	weed:      "🌿 ",
+	above:     "↑",
+	typical:   "≈",
+	below:     "↓",
+	pass:      "✓",
+	warn:      "!",
+	fail:      "✗",
+	inSeason:  "█",
+	thisWeek:  "│",
+	offSeason: "·",
+	border:    lipgloss.NormalBorder(),
+}
+
+var asciiGlyphs = glyphSet{
+	block:     "",
+	bullet:    "-",
+	sep:       " | ",
+	arrow:     "->",
+	warning:   "! ",
+	pill:      "",
+	above:     "+",
+	typical:   "~",
+	below:     "-",
+	pass:      "+",
+	warn:      "!",
+	fail:      "x",
+	inSeason:  "#",
+	thisWeek:  "|",
+	offSeason: ".",
+	border:    lipgloss.ASCIIBorder(),
+}
+
+// styles are the theme's text styles, bound to one lipgloss renderer so
+// they follow the color profile of its output.
+type styles struct {
+	r         *lipgloss.Renderer
+	palette   theme.Theme
+	apiColors bool
+
+	title          lipgloss.Style
+	header         lipgloss.Style
+	location       lipgloss.Style
+	cached         lipgloss.Style
+	recommendation lipgloss.Style
+	legend         lipgloss.Style
+	border         lipgloss.Style
+	good           lipgloss.Style
+	caution        lipgloss.Style
+	warning        lipgloss.Style
+	failure        lipgloss.Style
+	err            lipgloss.Style
+	seasonOn       lipgloss.Style
+	seasonOff      lipgloss.Style
+	today          lipgloss.Style
+}
+
+// newStyles builds a's styles for r.
+func newStyles(r *lipgloss.Renderer, a Appearance) styles {
+	p := a.Theme
+	return styles{
+		r:              r,
+		palette:        p,
+		apiColors:      a.APIColors,
+		title:          r.NewStyle().Bold(true).Foreground(color(p.Title)).MarginBottom(1),
+		header:         r.NewStyle().Bold(true).Foreground(color(p.Header)),
+		location:       r.NewStyle().Foreground(color(p.Text)),
+		cached:         r.NewStyle().Foreground(color(p.Faint)).Italic(true),
+		recommendation: r.NewStyle().Foreground(color(p.Text)),
+		legend:         r.NewStyle().Foreground(color(p.Faint)).Italic(true),
+		border:         r.NewStyle().Foreground(color(p.Border)),
+		good:           r.NewStyle().Foreground(color(p.Good)),
+		caution:        r.NewStyle().Foreground(color(p.Warning)),
+		warning:        r.NewStyle().Foreground(color(p.Warning)).Bold(true),
+		failure:        r.NewStyle().Foreground(color(p.Error)),
+		err:            r.NewStyle().Foreground(color(p.Error)).Bold(true),
+		seasonOn:       r.NewStyle().Foreground(color(p.Warning)),
+		seasonOff:      r.NewStyle().Foreground(color(p.Border)),
+		today:          r.NewStyle().Foreground(color(p.Accent)).Bold(true),
+	}
+}
+
+// level styles text in the color for a pollen level.
+func (s styles) level(level pollen.PollenLevel) lipgloss.Style {
+	return s.r.NewStyle().Foreground(s.levelColor(level))
+}
+
+// levelColor picks the color for a pollen level: the API's when enabled
+// and sent, otherwise the theme's for its index value.
+func (s styles) levelColor(level pollen.PollenLevel) lipgloss.TerminalColor {
+	if level.Level == nil {
+		return color(s.palette.NoData)
+	}
+	if s.apiColors && level.Color != "" {
+		return color(level.Color)
+	}
+	if v := *level.Level; v >= 0 && v < len(s.palette.Levels) {
+		return color(s.palette.Levels[v])
+	}
+	return color(s.palette.NoData)
+}
+
+// profileFor returns the color profile for writing to f in mode.
+func profileFor(f *os.File, mode string) termenv.Profile {
+	switch mode {
+	case theme.ColorNever:
+		return termenv.Ascii
+	case theme.ColorAlways:
+		// Use what the terminal type supports, but at least 256 colors:
+		// the themes turn muddy in 16, and piped output has no terminal
+		// type to go by.
+		p := termenv.NewOutput(f, termenv.WithTTY(true)).ColorProfile()
+		if p > termenv.ANSI256 {
+			p = termenv.ANSI256
+		}
+		return p
+	default:
+		// Plain text unless f is a terminal, and never with NO_COLOR.
+		return termenv.NewOutput(f).EnvColorProfile()
+	}
+}
+
+// color returns hex as a lipgloss color; empty leaves the terminal's own.
+func color(hex string) lipgloss.TerminalColor {
+	if hex == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(hex)
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/shunito/pollenow/internal/usage"
+)
+
+// RenderUsage prints API usage to stdout. See Renderer.Usage.
+func RenderUsage(month time.Time, summaries []usage.Summary, today int, caps usage.Caps) {
+	_ = stdout().Usage(month, summaries, today, caps)
+}
+
+// Usage writes API request counts and estimated cost for a month,
+// followed by today's count against the configured caps.
+func (r *Renderer) Usage(month time.Time, summaries []usage.Summary, today int, caps usage.Caps) error {
+	var b strings.Builder
+	fmt.Fprintln(&b, r.st.title.Render(r.msg.T("usage.title")))
+	fmt.Fprintln(&b, r.st.location.Render(r.msg.T("usage.month", r.msg.Month(month.Month()), month.Year())))
+	fmt.Fprintln(&b)
+
+	if len(summaries) == 0 {
+		fmt.Fprintln(&b, r.st.recommendation.Render(r.msg.T("usage.none")))
+	} else {
+		rows := make([][]string, 0, len(summaries)+1)
+		var calls, failed int
+		var cost float64
+		for _, s := range summaries {
+			rows = append(rows, []string{
+				s.API,
+				strconv.Itoa(s.Calls),
+				strconv.Itoa(s.Failed),
+				s.AvgLatency.String(),
+				fmt.Sprintf("$%.2f", s.Cost),
+			})
+			calls += s.Calls
+			failed += s.Failed
+			cost += s.Cost
+		}
+		rows = append(rows, []string{r.msg.T("usage.total"), strconv.Itoa(calls), strconv.Itoa(failed), "", fmt.Sprintf("$%.2f", cost)})
+
+		t := table.New().
+			Border(r.glyphs.border).
+			BorderStyle(r.st.border).
+			Headers(r.msg.T("usage.api"), r.msg.T("usage.requests"), r.msg.T("usage.failed"), r.msg.T("usage.latency"), r.msg.T("usage.cost")).
+			Rows(rows...).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				if row == table.HeaderRow || row == len(rows)-1 {
+					return r.st.header
+				}
+				return r.st.r.NewStyle()
+			})
+		fmt.Fprintln(&b, t)
+		fmt.Fprintln(&b, r.st.legend.Render(r.msg.T("usage.cost_note")))
+	}
+
+	fmt.Fprintln(&b)
+	line := r.msg.T("usage.today", today)
+	switch {
+	case caps.Hard > 0 && today >= caps.Hard:
+		fmt.Fprintln(&b, r.st.err.Render(r.msg.T("usage.hard", line, caps.Hard)))
+	case caps.Soft > 0 && today >= caps.Soft:
+		fmt.Fprintln(&b, r.st.warning.Render(r.msg.T("usage.soft", line, caps.Soft)))
+	default:
+		if caps.Hard > 0 {
+			line += r.msg.T("usage.of", caps.Hard)
+		}
+		fmt.Fprintln(&b, r.st.recommendation.Render(line))
+	}
+	fmt.Fprintln(&b)
+	return r.write(b.String())
+}
@@ -0,0 +1,81 @@
+package usage
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shunito/pollenow/internal/httpx"
+)
+
+// Recorder is an httpx.Client that logs every request it sends to a Ledger.
+// Place it closest to the network so each retry is counted.
+type Recorder struct {
+	next   httpx.Client
+	ledger *Ledger
+}
+
+// NewRecorder wraps next so its requests are recorded in ledger.
+func NewRecorder(next httpx.Client, ledger *Ledger) *Recorder {
+	return &Recorder{next: next, ledger: ledger}
+}
+
+// Do sends req and records it. Ledger errors never fail the request.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := r.next.Do(req)
+
+	rec := Record{
+		Time:      start,
+		API:       apiFor(req),
+		Endpoint:  req.URL.Host + req.URL.Path,
+		LatencyMS: time.Since(start).Milliseconds(),
+	}
+	if resp != nil {
+		rec.Status = resp.StatusCode
+	}
+	_ = r.ledger.Add(rec)
+
+	return resp, err
+}
+
+// Caps limit the number of upstream requests per local day. Zero means
+// no limit.
+type Caps struct {
+	Soft int // warn once reached
+	Hard int // refuse requests once reached
+}
+
+// Limiter is an httpx.Client that enforces daily caps from the Ledger. Place
+// it outside any retrying client so a refusal is final.
+type Limiter struct {
+	next   httpx.Client
+	ledger *Ledger
+	caps   Caps
+	warn   func(msg string)
+	warned bool
+}
+
+// NewLimiter wraps next so requests are refused once the hard cap is
+// reached. warn is called once when the soft cap is reached; it may be nil.
+func NewLimiter(next httpx.Client, ledger *Ledger, caps Caps, warn func(msg string)) *Limiter {
+	return &Limiter{next: next, ledger: ledger, caps: caps, warn: warn}
+}
+
+// Do checks today's count against the caps, then sends req.
+func (l *Limiter) Do(req *http.Request) (*http.Response, error) {
+	if l.caps.Soft > 0 || l.caps.Hard > 0 {
+		n, err := l.ledger.Today(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if l.caps.Hard > 0 && n >= l.caps.Hard {
+			return nil, fmt.Errorf("%w: %d of %d requests used today — cached forecasts still work; raise usage.hard_daily_cap or try again tomorrow", ErrHardCap, n, l.caps.Hard)
+		}
+		if l.caps.Soft > 0 && n >= l.caps.Soft && !l.warned && l.warn != nil {
+			l.warned = true
+			l.warn(fmt.Sprintf("%d of %d API requests used today (usage.soft_daily_cap)", n, l.caps.Soft))
+		}
+	}
+	return l.next.Do(req)
+}
@@ -0,0 +1,234 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shunito/pollenow/internal/xdg"
+)
+
+var (
+	ErrHardCap = errors.New("daily API request cap reached")
+)
+
+// API names used in the ledger.
+const (
+	APIGeocoding = "geocoding"
+	APIPollen    = "pollen"
+)
+
+// PricePer1000 is the list price in USD per 1,000 requests, before any
+// free monthly credit. Used only for estimates.
+var PricePer1000 = map[string]float64{
+	APIGeocoding: 5.00,
+	APIPollen:    10.00,
+}
+
+// Record is one upstream HTTP request.
+type Record struct {
+	Time      time.Time `json:"time"`
+	API       string    `json:"api"`      // "geocoding", "pollen", or the host for anything else
+	Endpoint  string    `json:"endpoint"` // host and path, never the query string
+	Status    int       `json:"status"`   // 0 when no response was received
+	LatencyMS int64     `json:"latencyMs"`
+}
+
+// Failed reports whether the request got no response or an error status.
+func (r Record) Failed() bool {
+	return r.Status == 0 || r.Status >= 400
+}
+
+// Ledger is an append-only JSON Lines log of upstream requests.
+type Ledger struct {
+	path string
+	err  error // set when no state directory could be resolved
+	mu   sync.Mutex
+
+	// day and count cache Today's result, so caps are checked without
+	// rereading the ledger on every request. Adds through this Ledger keep
+	// the count current.
+	day     time.Time
+	count   int
+	counted bool
+}
+
+// New creates a Ledger. If path is empty, uses $XDG_STATE_HOME/pollenow/usage.jsonl.
+// If that cannot be resolved, every operation returns the resolution error.
+func New(path string) *Ledger {
+	l := &Ledger{path: path}
+	if path == "" {
+		state, err := xdg.StateDir()
+		l.path, l.err = filepath.Join(state, ledgerFile), err
+	}
+	return l
+}
+
+// ledgerFile is the ledger's name in the state directory.
+const ledgerFile = "usage.jsonl"
+
+// MigrateLedger moves a ledger that earlier versions kept in
+// $XDG_DATA_HOME/pollenow into the state directory. An existing ledger there
+// is never overwritten. It returns a description of the move, or "" if
+// there was nothing to move.
+func MigrateLedger() (string, error) {
+	data, err := xdg.DataDir()
+	if err != nil {
+		return "", nil
+	}
+	state, err := xdg.StateDir()
+	if err != nil {
+		return "", nil
+	}
+	from, to := filepath.Join(data, ledgerFile), filepath.Join(state, ledgerFile)
+	ok, err := xdg.Migrate(from, to)
+	if !ok {
+		return "", err
+	}
+	return fmt.Sprintf("moved usage ledger from %s to %s", from, to), err
+}
+
+// Add appends a record to the ledger.
+func (l *Ledger) Add(r Record) error {
+	if l.err != nil {
+		return l.err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("creating usage dir: %w", err)
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshaling usage record: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing usage ledger: %w", err)
+	}
+	if l.counted && !r.Time.Before(l.day) && r.Time.Before(l.day.AddDate(0, 0, 1)) {
+		l.count++
+	}
+	return nil
+}
+
+// Range returns records with times in [from, to), oldest first.
+func (l *Ledger) Range(from, to time.Time) ([]Record, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	f, err := os.Open(l.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening usage ledger: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			return nil, fmt.Errorf("parsing usage line %d: %w", n, err)
+		}
+		if !r.Time.Before(from) && r.Time.Before(to) {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading usage ledger: %w", err)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+	return records, nil
+}
+
+// Today returns the number of requests made since local midnight. The
+// ledger is read once per day; after that, only requests added through l
+// are counted, not those other processes make meanwhile.
+func (l *Ledger) Today(now time.Time) (int, error) {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.counted && l.day.Equal(start) {
+		return l.count, nil
+	}
+	records, err := l.Range(start, start.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, err
+	}
+	l.day, l.count, l.counted = start, len(records), true
+	return l.count, nil
+}
+
+// Summary aggregates the requests to one API.
+type Summary struct {
+	API        string
+	Calls      int
+	Failed     int
+	AvgLatency time.Duration
+	Cost       float64 // estimated, USD
+}
+
+// Summarize groups records by API, sorted by name.
+func Summarize(records []Record) []Summary {
+	byAPI := map[string]*Summary{}
+	latency := map[string]int64{}
+	for _, r := range records {
+		s, ok := byAPI[r.API]
+		if !ok {
+			s = &Summary{API: r.API}
+			byAPI[r.API] = s
+		}
+		s.Calls++
+		if r.Failed() {
+			s.Failed++
+		}
+		latency[r.API] += r.LatencyMS
+	}
+
+	out := make([]Summary, 0, len(byAPI))
+	for api, s := range byAPI {
+		s.AvgLatency = time.Duration(latency[api]/int64(s.Calls)) * time.Millisecond
+		s.Cost = float64(s.Calls) / 1000 * PricePer1000[api]
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].API < out[j].API })
+	return out
+}
+
+// apiFor names the API a request goes to. It goes by path rather than
+// host so requests through gateways or regional endpoints still count.
+func apiFor(req *http.Request) string {
+	p := req.URL.Path
+	switch {
+	case strings.Contains(p, "/geocode/"):
+		return APIGeocoding
+	case strings.Contains(p, "forecast:lookup"), strings.Contains(p, "/mapTypes/"):
+		return APIPollen
+	}
+	return req.URL.Hostname()
+}
@@ -0,0 +1,165 @@
+package usage
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type mockHTTPClient struct {
+	status int
+	err    error
+	calls  int
+}
+
+func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &http.Response{StatusCode: m.status, Body: io.NopCloser(strings.NewReader(""))}, nil
+}
+
+func TestRecorder(t *testing.T) {
+	ledger := New(filepath.Join(t.TempDir(), "usage.jsonl"))
+	ok := NewRecorder(&mockHTTPClient{status: 200}, ledger)
+	down := NewRecorder(&mockHTTPClient{err: errors.New("connection refused")}, ledger)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://pollen.googleapis.com/v1/forecast:lookup?key=secret&days=5", nil)
+	ok.Do(req)
+	req, _ = http.NewRequest(http.MethodGet, "https://maps.googleapis.com/maps/api/geocode/json?key=secret", nil)
+	down.Do(req)
+
+	records, err := ledger.Range(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Range failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if r := records[0]; r.API != APIPollen || r.Status != 200 || r.Endpoint != "pollen.googleapis.com/v1/forecast:lookup" {
+		t.Errorf("pollen record: %+v", r)
+	}
+	if r := records[1]; r.API != APIGeocoding || r.Status != 0 || !r.Failed() {
+		t.Errorf("geocoding record: %+v", r)
+	}
+	for _, r := range records {
+		if strings.Contains(r.Endpoint, "secret") {
+			t.Errorf("endpoint leaks the query string: %q", r.Endpoint)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	ledger := New(filepath.Join(t.TempDir(), "usage.jsonl"))
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		ledger.Add(Record{Time: now, API: APIPollen, Status: 200})
+	}
+	// Yesterday's requests do not count toward today's caps.
+	ledger.Add(Record{Time: now.AddDate(0, 0, -1), API: APIPollen, Status: 200})
+
+	var warnings []string
+	next := &mockHTTPClient{status: 200}
+	l := NewLimiter(next, ledger, Caps{Soft: 3, Hard: 5}, func(msg string) { warnings = append(warnings, msg) })
+
+	req, _ := http.NewRequest(http.MethodGet, "https://pollen.googleapis.com/v1/forecast:lookup", nil)
+	l.Do(req)
+	l.Do(req)
+	if next.calls != 2 || len(warnings) != 1 {
+		t.Errorf("over soft cap: %d calls, %d warnings; want 2 calls and 1 warning", next.calls, len(warnings))
+	}
+
+	ledger.Add(Record{Time: now, API: APIPollen, Status: 200})
+	ledger.Add(Record{Time: now, API: APIPollen, Status: 200})
+	if _, err := l.Do(req); !errors.Is(err, ErrHardCap) {
+		t.Errorf("expected ErrHardCap, got %v", err)
+	}
+	if next.calls != 2 {
+		t.Error("request over the hard cap reached the network")
+	}
+}
+
+func TestTodayCached(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	ledger := New(path)
+	now := time.Now()
+	ledger.Add(Record{Time: now, API: APIPollen, Status: 200})
+	if n, _ := ledger.Today(now); n != 1 {
+		t.Fatalf("Today = %d, want 1", n)
+	}
+
+	// Adds through the ledger keep the cached count current; yesterday's
+	// do not count.
+	ledger.Add(Record{Time: now, API: APIGeocoding, Status: 200})
+	ledger.Add(Record{Time: now.AddDate(0, 0, -1), API: APIPollen, Status: 200})
+	if n, _ := ledger.Today(now); n != 2 {
+		t.Errorf("Today after adds = %d, want 2", n)
+	}
+
+	// The file is not reread for the same day.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := ledger.Today(now); n != 2 {
+		t.Errorf("Today after removing the file = %d, want the cached 2", n)
+	}
+	if n, _ := ledger.Today(now.AddDate(0, 0, 1)); n != 0 {
+		t.Errorf("Today on the next day = %d, want 0", n)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	records := []Record{
+		{API: APIPollen, Status: 200, LatencyMS: 100},
+		{API: APIPollen, Status: 500, LatencyMS: 300},
+		{API: APIGeocoding, Status: 200, LatencyMS: 50},
+	}
+	got := Summarize(records)
+	if len(got) != 2 || got[0].API != APIGeocoding || got[1].API != APIPollen {
+		t.Fatalf("got %+v", got)
+	}
+	p := got[1]
+	if p.Calls != 2 || p.Failed != 1 || p.AvgLatency != 200*time.Millisecond {
+		t.Errorf("pollen summary: %+v", p)
+	}
+	if want := 2.0 / 1000 * PricePer1000[APIPollen]; p.Cost != want {
+		t.Errorf("Cost: got %v, want %v", p.Cost, want)
+	}
+}
+
+func TestMigrateLedger(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+
+	old := filepath.Join(dir, "data", "pollenow", "usage.jsonl")
+	if err := os.MkdirAll(filepath.Dir(old), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(old, []byte(`{"time":"2025-06-15T12:00:00Z","api":"pollen","status":200}`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := MigrateLedger()
+	if err != nil || msg == "" {
+		t.Fatalf("MigrateLedger() = %q, %v", msg, err)
+	}
+	day := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	records, err := New("").Range(day, day.AddDate(0, 0, 1))
+	if err != nil || len(records) != 1 || records[0].API != APIPollen {
+		t.Errorf("ledger after migration: %+v, %v", records, err)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("old ledger still present: %v", err)
+	}
+
+	if msg, err := MigrateLedger(); msg != "" || err != nil {
+		t.Errorf("second MigrateLedger() = %q, %v, want nothing to do", msg, err)
+	}
+}
@@ -0,0 +1,162 @@
+package xdg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const appName = "pollenow"
+
+var (
+	ErrNoDirectory = errors.New("cannot resolve directory")
+)
+
+// base describes one XDG base directory and its fallback under $HOME.
+type base struct {
+	env      string
+	fallback []string
+	purpose  string
+}
+
+var (
+	configBase = base{"XDG_CONFIG_HOME", []string{".config"}, "config"}
+	cacheBase  = base{"XDG_CACHE_HOME", []string{".cache"}, "cache"}
+	dataBase   = base{"XDG_DATA_HOME", []string{".local", "share"}, "data"}
+	stateBase  = base{"XDG_STATE_HOME", []string{".local", "state"}, "state"}
+)
+
+// userHomeDir is a variable so tests can simulate a missing home directory.
+var userHomeDir = os.UserHomeDir
+
+// ConfigDir returns $XDG_CONFIG_HOME/pollenow, defaulting to ~/.config/pollenow.
+func ConfigDir() (string, error) { return configBase.dir() }
+
+// CacheDir returns $XDG_CACHE_HOME/pollenow, defaulting to ~/.cache/pollenow.
+func CacheDir() (string, error) { return cacheBase.dir() }
+
+// DataDir returns $XDG_DATA_HOME/pollenow, defaulting to ~/.local/share/pollenow.
+func DataDir() (string, error) { return dataBase.dir() }
+
+// StateDir returns $XDG_STATE_HOME/pollenow, defaulting to ~/.local/state/pollenow.
+func StateDir() (string, error) { return stateBase.dir() }
+
+// dir resolves the application directory. Relative values of the XDG
+// variable are ignored, as the specification requires.
+func (b base) dir() (string, error) {
+	if v := os.Getenv(b.env); v != "" && filepath.IsAbs(v) {
+		return filepath.Join(v, appName), nil
+	}
+	return b.legacyDir()
+}
+
+// legacyDir returns the directory PolleNow used before honoring XDG.
+func (b base) legacyDir() (string, error) {
+	home, err := userHomeDir()
+	if err != nil || home == "" || !filepath.IsAbs(home) {
+		return "", fmt.Errorf("%w for %s files: set %s or HOME to an absolute path", ErrNoDirectory, b.purpose, b.env)
+	}
+	return filepath.Join(append(append([]string{home}, b.fallback...), appName)...), nil
+}
+
+// MigrateLegacy moves files written to the old hard-coded locations
+// (~/.config/pollenow, ~/.cache/pollenow, ~/.local/share/pollenow,
+// ~/.local/state/pollenow) into the
+// directories selected by the XDG variables. Existing destinations are never
+// overwritten. It returns a description of each move performed.
+func MigrateLegacy() ([]string, error) {
+	var moved []string
+	for _, b := range []base{configBase, cacheBase, dataBase, stateBase} {
+		from, err := b.legacyDir()
+		if err != nil {
+			continue
+		}
+		to, err := b.dir()
+		if err != nil {
+			continue
+		}
+		ok, err := Migrate(from, to)
+		if err != nil {
+			return moved, err
+		}
+		if ok {
+			moved = append(moved, fmt.Sprintf("moved %s files from %s to %s", b.purpose, from, to))
+		}
+	}
+	return moved, nil
+}
+
+// Migrate moves the directory from to the path to. It does nothing and
+// returns false if the paths are equal, from does not exist, or to already
+// exists.
+func Migrate(from, to string) (bool, error) {
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return false, nil
+	}
+	if _, err := os.Stat(from); err != nil {
+		return false, nil
+	}
+	if _, err := os.Stat(to); err == nil {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return false, fmt.Errorf("migrating %s: %w", from, err)
+	}
+	if err := os.Rename(from, to); err == nil {
+		return true, nil
+	}
+
+	// Rename fails across filesystems; fall back to copy and remove.
+	if err := copyDir(from, to); err != nil {
+		os.RemoveAll(to)
+		return false, fmt.Errorf("migrating %s: %w", from, err)
+	}
+	if err := os.RemoveAll(from); err != nil {
+		return true, fmt.Errorf("removing %s after migration: %w", from, err)
+	}
+	return true, nil
+}
+
+func copyDir(from, to string) error {
+	return filepath.WalkDir(from, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		return copyFile(p, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(from, to string, perm fs.FileMode) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(to, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
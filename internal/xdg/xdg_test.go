@@ -0,0 +1,149 @@
+package xdg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirsHonorXDG(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, "cfg"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(tmp, "cache"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmp, "data"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(tmp, "state"))
+
+	tests := []struct {
+		fn   func() (string, error)
+		want string
+	}{
+		{ConfigDir, filepath.Join(tmp, "cfg", "pollenow")},
+		{CacheDir, filepath.Join(tmp, "cache", "pollenow")},
+		{DataDir, filepath.Join(tmp, "data", "pollenow")},
+		{StateDir, filepath.Join(tmp, "state", "pollenow")},
+	}
+	for _, tt := range tests {
+		got, err := tt.fn()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestDirsFallBackToHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", "relative/path") // must be ignored
+	t.Setenv("XDG_DATA_HOME", "")
+
+	got, err := CacheDir()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(home, ".cache", "pollenow"); got != want {
+		t.Errorf("CacheDir: got %q, want %q", got, want)
+	}
+
+	got, _ = DataDir()
+	if want := filepath.Join(home, ".local", "share", "pollenow"); got != want {
+		t.Errorf("DataDir: got %q, want %q", got, want)
+	}
+}
+
+func TestDirsNoHome(t *testing.T) {
+	orig := userHomeDir
+	userHomeDir = func() (string, error) { return "", errors.New("$HOME is not defined") }
+	defer func() { userHomeDir = orig }()
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	_, err := ConfigDir()
+	if !errors.Is(err, ErrNoDirectory) {
+		t.Fatalf("expected ErrNoDirectory, got %v", err)
+	}
+}
+
+func TestMigrateLegacy(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg-config"))
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "xdg-state"))
+
+	legacy := filepath.Join(home, ".config", "pollenow")
+	if err := os.MkdirAll(legacy, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "config.yaml"), []byte("api_key: x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	legacyState := filepath.Join(home, ".local", "state", "pollenow")
+	if err := os.MkdirAll(legacyState, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacyState, "usage.jsonl"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	moved, err := MigrateLegacy()
+	if err != nil {
+		t.Fatalf("MigrateLegacy failed: %v", err)
+	}
+	if len(moved) != 2 {
+		t.Fatalf("got %d moves, want 2: %v", len(moved), moved)
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, "xdg-config", "pollenow", "config.yaml"))
+	if err != nil || string(data) != "api_key: x\n" {
+		t.Errorf("migrated config: got %q, %v", data, err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Error("legacy directory should be gone after migration")
+	}
+	if _, err := os.Stat(filepath.Join(home, "xdg-state", "pollenow", "usage.jsonl")); err != nil {
+		t.Errorf("migrated state: %v", err)
+	}
+
+	// Running again is a no-op.
+	moved, err = MigrateLegacy()
+	if err != nil || len(moved) != 0 {
+		t.Errorf("second run: got %v, %v", moved, err)
+	}
+}
+
+func TestMigrateKeepsExistingDestination(t *testing.T) {
+	tmp := t.TempDir()
+	from, to := filepath.Join(tmp, "old"), filepath.Join(tmp, "new")
+	os.MkdirAll(from, 0o755)
+	os.MkdirAll(to, 0o755)
+
+	ok, err := Migrate(from, to)
+	if err != nil || ok {
+		t.Errorf("Migrate: got %v, %v; want false, nil", ok, err)
+	}
+	if _, err := os.Stat(from); err != nil {
+		t.Error("source should be left alone when destination exists")
+	}
+}
+
+func TestCopyDir(t *testing.T) {
+	tmp := t.TempDir()
+	from, to := filepath.Join(tmp, "old"), filepath.Join(tmp, "new")
+	os.MkdirAll(filepath.Join(from, "history"), 0o755)
+	os.WriteFile(filepath.Join(from, "history", "94025.json"), []byte("[]"), 0o600)
+
+	if err := copyDir(from, to); err != nil {
+		t.Fatalf("copyDir failed: %v", err)
+	}
+	info, err := os.Stat(filepath.Join(to, "history", "94025.json"))
+	if err != nil {
+		t.Fatalf("copied file missing: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("permissions: got %v, want 0600", info.Mode().Perm())
+	}
+}